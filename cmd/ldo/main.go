@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lhpqaq/ldo/internal/cli"
 	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/config"
+	"github.com/lhpqaq/ldo/internal/state"
 	"github.com/lhpqaq/ldo/internal/ui"
 )
 
@@ -15,42 +19,85 @@ func main() {
 	username := os.Getenv("LINUXDO_USERNAME")
 	password := os.Getenv("LINUXDO_PASSWORD")
 
-	if username == "" || password == "" {
-		log.Fatal("请设置 LINUXDO_USERNAME 和 LINUXDO_PASSWORD 环境变量")
-	}
-
-	// Determine mode: CLI or TUI
+	// Determine mode: CLI or TUI, and whether to read from the local cache only
 	mode := os.Getenv("LINUXDO_MODE")
-	if len(os.Args) > 1 {
-		if os.Args[1] == "--cli" || os.Args[1] == "-c" {
+	offline := false
+	for _, arg := range os.Args[1:] {
+		switch arg {
+		case "--cli", "-c":
 			mode = "cli"
-		} else if os.Args[1] == "--tui" || os.Args[1] == "-t" {
+		case "--tui", "-t":
 			mode = "tui"
+		case "--offline":
+			offline = true
 		}
 	}
 
-	fmt.Println("正在连接 Linux.do 论坛...")
+	if !offline && (username == "" || password == "") {
+		log.Fatal("请设置 LINUXDO_USERNAME 和 LINUXDO_PASSWORD 环境变量")
+	}
 
-	c, err := client.NewClient("https://linux.do", username, password)
-	if err != nil {
-		log.Fatalf("客户端初始化失败: %v", err)
+	cfg, cfgErr := config.Load()
+	if cfgErr != nil {
+		cfgErr = fmt.Errorf("读取配置失败，使用默认配置: %w", cfgErr)
+		fmt.Printf("⚠️ %v\n", cfgErr)
 	}
 
-	fmt.Printf("✅ 登录成功! 用户: %s\n", c.GetUsername())
+	var c *client.Client
+	if offline {
+		fmt.Println("📴 离线模式，只显示本地缓存的内容")
+		c = client.NewOfflineClient("https://linux.do", username)
+	} else {
+		fmt.Println("正在连接 Linux.do 论坛...")
+
+		var err error
+		c, err = client.NewClient("https://linux.do", username, password)
+		if err != nil {
+			log.Fatalf("客户端初始化失败: %v", err)
+		}
+
+		fmt.Printf("✅ 登录成功! 用户: %s\n", c.GetUsername())
+	}
 
 	if mode == "cli" {
 		fmt.Println("启动 CLI 摸鱼模式...")
-		cliMode := cli.NewCLI(c)
+		cliMode := cli.NewCLI(c, cfg)
 		cliMode.Run()
 	} else {
 		fmt.Println("启动 TUI 终端界面...")
+		// TUI 使用备用屏幕，启动时的警告交给界面显示
+		warnings := []error{cfgErr}
+		if err := ui.LoadKeyMap(cfg.UI); err != nil {
+			warnings = append(warnings, fmt.Errorf("快捷键配置有误，已忽略自定义部分: %w", err))
+		}
+		if err := ui.LoadTheme(cfg.UI); err != nil {
+			warnings = append(warnings, fmt.Errorf("主题配置有误，使用自动检测的主题: %w", err))
+		}
+		st, err := state.Load()
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("读取阅读进度失败，从头开始: %w", err))
+		}
 		p := tea.NewProgram(
-			ui.NewModel(c),
+			ui.NewModel(c, cfg, st, warnings...),
 			tea.WithAltScreen(),
 			tea.WithMouseCellMotion(),
 		)
 
-		if _, err := p.Run(); err != nil {
+		// 关闭终端时正常退出，保存阅读进度（SIGINT、SIGTERM 由 Bubble Tea 处理）
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go func() {
+			<-hup
+			p.Quit()
+		}()
+
+		final, err := p.Run()
+		if m, ok := final.(ui.Model); ok {
+			if err := m.SaveState(); err != nil {
+				fmt.Printf("⚠️ 保存阅读进度失败: %v\n", err)
+			}
+		}
+		if err != nil {
 			log.Fatal(err)
 		}
 	}
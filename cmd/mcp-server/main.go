@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/config"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -15,6 +16,7 @@ import (
 type LinuxDoServer struct {
 	client *client.Client
 	initErr error
+	config  *config.Config
 }
 
 func main() {
@@ -29,12 +31,18 @@ func main() {
 	fmt.Fprintf(os.Stderr, "正在初始化 Linux.do 客户端...\n")
 	fmt.Fprintf(os.Stderr, "用户名: %s\n", username)
 
+	cfg, cfgErr := config.Load()
+	if cfgErr != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ 读取配置失败，使用默认配置: %v\n", cfgErr)
+	}
+
 	// 初始化客户端（会尝试使用已保存的登录状态）
 	c, err := client.NewClient("https://linux.do", username, password)
 
 	ldoServer := &LinuxDoServer{
 		client: c,
 		initErr: err,
+		config:  cfg,
 	}
 
 	if err != nil {
@@ -207,6 +215,88 @@ func (s *LinuxDoServer) registerTools(mcpServer *server.MCPServer) {
 			Required: []string{"topic_id", "post_ids"},
 		},
 	}, s.handleGetPosts)
+
+	// 9. 获取用户资料
+	mcpServer.AddTool(mcp.Tool{
+		Name:        "get_user_profile",
+		Description: "获取指定用户的资料和统计摘要（发帖数、获赞数、访问天数等）",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"username": map[string]interface{}{
+					"type":        "string",
+					"description": "用户名",
+				},
+			},
+			Required: []string{"username"},
+		},
+	}, s.handleGetUserProfile)
+
+	// 10. 获取用户动态
+	mcpServer.AddTool(mcp.Tool{
+		Name:        "get_user_activity",
+		Description: "获取指定用户的动态（发帖、回复、点赞）",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"username": map[string]interface{}{
+					"type":        "string",
+					"description": "用户名",
+				},
+				"filter": map[string]interface{}{
+					"type":        "string",
+					"description": "动态类型：posts(发帖和回复，默认)、topics(发帖)、replies(回复)、likes(点赞)",
+				},
+				"offset": map[string]interface{}{
+					"type":        "number",
+					"description": "偏移量，默认为0",
+				},
+			},
+			Required: []string{"username"},
+		},
+	}, s.handleGetUserActivity)
+
+	// 11. 上传文件（仅限配置中允许的目录）
+	mcpServer.AddTool(mcp.Tool{
+		Name:        "upload_file",
+		Description: "上传本地文件（图片或附件），返回可插入回帖的 Markdown。只能上传配置中 mcp.upload_dirs 允许的目录下的文件",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "本地文件路径",
+				},
+			},
+			Required: []string{"path"},
+		},
+	}, s.handleUploadFile)
+
+	// 举报帖子需要在配置中显式开启，避免 AI 助手误操作
+	if s.config.MCP.EnableFlag {
+		mcpServer.AddTool(mcp.Tool{
+			Name:        "flag_post",
+			Description: "举报帖子给版主处理（偏离主题、不当内容、垃圾信息或其他）",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"post_id": map[string]interface{}{
+						"type":        "number",
+						"description": "帖子ID",
+					},
+					"type": map[string]interface{}{
+						"type":        "string",
+						"description": "举报类型：off_topic、inappropriate、spam、other",
+					},
+					"message": map[string]interface{}{
+						"type":        "string",
+						"description": "举报说明，type为other时必填",
+					},
+				},
+				Required: []string{"post_id", "type"},
+			},
+		}, s.handleFlagPost)
+	}
 }
 
 // 检查客户端是否可用
@@ -437,3 +527,131 @@ func (s *LinuxDoServer) handleGetPosts(ctx context.Context, request mcp.CallTool
 	result, _ := json.MarshalIndent(posts, "", "  ")
 	return mcp.NewToolResultText(string(result)), nil
 }
+
+func (s *LinuxDoServer) handleGetUserProfile(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := s.checkClient(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var params struct {
+		Username string `json:"username"`
+	}
+
+	argsBytes, _ := json.Marshal(request.Params.Arguments)
+	if err := json.Unmarshal(argsBytes, &params); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("参数解析失败: %v", err)), nil
+	}
+
+	profile, err := s.client.GetUserProfile(params.Username)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("获取用户资料失败: %v", err)), nil
+	}
+
+	summary, err := s.client.GetUserSummary(params.Username)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("获取用户摘要失败: %v", err)), nil
+	}
+
+	result, _ := json.MarshalIndent(map[string]interface{}{
+		"profile": profile,
+		"summary": summary,
+	}, "", "  ")
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *LinuxDoServer) handleGetUserActivity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := s.checkClient(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var params struct {
+		Username string  `json:"username"`
+		Filter   string  `json:"filter"`
+		Offset   float64 `json:"offset"`
+	}
+
+	argsBytes, _ := json.Marshal(request.Params.Arguments)
+	if err := json.Unmarshal(argsBytes, &params); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("参数解析失败: %v", err)), nil
+	}
+
+	var filter string
+	switch params.Filter {
+	case "topics":
+		filter = client.UserActionFilterTopics
+	case "replies":
+		filter = client.UserActionFilterReplies
+	case "likes":
+		filter = client.UserActionFilterLikes
+	default:
+		filter = client.UserActionFilterPosts
+	}
+
+	actions, err := s.client.GetUserActions(params.Username, filter, int(params.Offset))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("获取用户动态失败: %v", err)), nil
+	}
+
+	result, _ := json.MarshalIndent(actions, "", "  ")
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *LinuxDoServer) handleFlagPost(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := s.checkClient(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var params struct {
+		PostID  float64 `json:"post_id"`
+		Type    string  `json:"type"`
+		Message string  `json:"message"`
+	}
+
+	argsBytes, _ := json.Marshal(request.Params.Arguments)
+	if err := json.Unmarshal(argsBytes, &params); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("参数解析失败: %v", err)), nil
+	}
+
+	flagType, err := client.ParseFlagType(params.Type)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if err := s.client.FlagPost(int(params.PostID), flagType, params.Message); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("举报失败: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("✅ 已举报帖子 #%d (%s)", int(params.PostID), flagType.Label)), nil
+}
+
+func (s *LinuxDoServer) handleUploadFile(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := s.checkClient(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var params struct {
+		Path string `json:"path"`
+	}
+
+	argsBytes, _ := json.Marshal(request.Params.Arguments)
+	if err := json.Unmarshal(argsBytes, &params); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("参数解析失败: %v", err)), nil
+	}
+
+	path, err := s.config.MCP.AllowedUploadPath(params.Path)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	upload, err := s.client.UploadFile(path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("上传失败: %v", err)), nil
+	}
+
+	result, _ := json.MarshalIndent(map[string]interface{}{
+		"markdown":  upload.Markdown(),
+		"short_url": upload.ShortURL,
+		"url":       upload.URL,
+	}, "", "  ")
+	return mcp.NewToolResultText(string(result)), nil
+}
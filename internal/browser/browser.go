@@ -0,0 +1,57 @@
+// Package browser 在系统默认浏览器中打开链接，供命令行和 TUI 共用
+package browser
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// waitTimeout 等待打开命令退出的时间。xdg-open 等命令通常很快退出；
+// 超时仍在运行时（例如直接启动了浏览器）视为已经打开，进程在后台回收
+var waitTimeout = 2 * time.Second
+
+// command 返回当前平台打开链接的命令
+var command = func(url string) (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url), nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return exec.Command("xdg-open", url), nil
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url), nil
+	}
+	return nil, fmt.Errorf("不支持在 %s 上打开浏览器，请手动打开 %s", runtime.GOOS, url)
+}
+
+// Open 在浏览器中打开链接，命令无法启动或以错误退出时返回错误。
+// 命令的输出不会写到终端，以免破坏界面
+func Open(url string) error {
+	cmd, err := command(url)
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("打开浏览器失败: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("打开浏览器失败: %w: %s", err, msg)
+			}
+			return fmt.Errorf("打开浏览器失败: %w", err)
+		}
+		return nil
+	case <-time.After(waitTimeout):
+		return nil
+	}
+}
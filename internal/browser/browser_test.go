@@ -0,0 +1,60 @@
+package browser
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fake 用 sh 脚本代替打开命令
+func fake(t *testing.T, script string) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("没有 sh")
+	}
+	old, oldTimeout := command, waitTimeout
+	t.Cleanup(func() { command, waitTimeout = old, oldTimeout })
+	command = func(url string) (*exec.Cmd, error) {
+		return exec.Command("sh", "-c", script, "sh", url), nil
+	}
+	waitTimeout = 200 * time.Millisecond
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   string // 错误应包含的文字，为空时应成功
+	}{
+		{"成功", `exit 0`, ""},
+		{"命令失败", `echo "no method available for $1" >&2; exit 3`, "no method available for https://a.example"},
+		{"命令一直运行", `sleep 5`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake(t, tt.script)
+			err := Open("https://a.example")
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Open: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v，应包含 %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenStartError(t *testing.T) {
+	old := command
+	t.Cleanup(func() { command = old })
+	command = func(url string) (*exec.Cmd, error) {
+		return exec.Command("ldo-no-such-opener", url), nil
+	}
+	if err := Open("https://a.example"); err == nil {
+		t.Fatal("命令不存在时应返回错误")
+	}
+}
@@ -3,16 +3,27 @@ package cli
 import (
 	"bufio"
 	"fmt"
+	"image"
 	"os"
-	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/lhpqaq/ldo/internal/browser"
 	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/config"
+	"github.com/lhpqaq/ldo/internal/editor"
+	"github.com/lhpqaq/ldo/internal/emoji"
+	"github.com/lhpqaq/ldo/internal/render"
+	"github.com/lhpqaq/ldo/internal/termimg"
 )
 
+// draftSaveInterval 回复时自动保存草稿的间隔
+const draftSaveInterval = 15 * time.Second
+
 type CLI struct {
 	client         *client.Client
+	config         *config.Config
 	currentTopic   *client.TopicDetail
 	posts          []client.Post
 	allPostIDs     []int
@@ -20,18 +31,23 @@ type CLI struct {
 	topics         []client.Topic
 	users          map[int]string
 	filter         string
+	period         string            // top 列表的时间范围
+	order          client.TopicOrder // 话题列表的排序方式
 	moreURL        string
 	reader         *bufio.Reader
 	searchResults  []client.SearchResult
 	searchQuery    string
 	searchPage     int
 	isSearchMode   bool
+	links          []render.LinkRef // 最近一次 links 命令列出的链接
 }
 
-func NewCLI(c *client.Client) *CLI {
+func NewCLI(c *client.Client, cfg *config.Config) *CLI {
 	return &CLI{
 		client: c,
+		config: cfg,
 		filter: "latest",
+		period: "weekly",
 		users:  make(map[int]string),
 		reader: bufio.NewReader(os.Stdin),
 	}
@@ -46,7 +62,11 @@ func (c *CLI) Run() {
 	c.loadTopics()
 
 	for {
-		fmt.Print("linuxdo> ")
+		if c.client.Offline() {
+			fmt.Print("linuxdo (offline)> ")
+		} else {
+			fmt.Print("linuxdo> ")
+		}
 		input, _ := c.reader.ReadString('\n')
 		input = strings.TrimSpace(input)
 
@@ -72,9 +92,37 @@ func (c *CLI) Run() {
 		case "more":
 			c.cmdMore()
 		case "reply":
-			c.cmdReply()
+			if len(args) > 0 && args[0] == "-e" {
+				c.cmdReplyEditor()
+			} else {
+				c.cmdReply()
+			}
+		case "quote":
+			c.cmdQuote(args)
 		case "like":
 			c.cmdLike(args)
+		case "react":
+			c.cmdReact(args)
+		case "flag":
+			c.cmdFlag(args)
+		case "upload":
+			c.cmdUpload(args)
+		case "img", "images":
+			c.cmdImg(args)
+		case "links":
+			c.cmdLinks(args)
+		case "follow":
+			c.cmdFollow(args)
+		case "vote":
+			c.cmdVote(args)
+		case "unvote":
+			c.cmdUnvote(args)
+		case "watch":
+			c.cmdWatch(args)
+		case "mute":
+			c.cmdMute(args)
+		case "whois":
+			c.cmdWhois(args)
 		case "jump":
 			c.cmdJump(args)
 		case "last":
@@ -83,6 +131,10 @@ func (c *CLI) Run() {
 			c.cmdBrowser()
 		case "filter":
 			c.cmdFilter(args)
+		case "period":
+			c.cmdPeriod(args)
+		case "sort":
+			c.cmdSort(args)
 		case "refresh":
 			c.cmdRefresh()
 		case "search", "find":
@@ -111,9 +163,9 @@ func (c *CLI) loadTopics() {
 	case "new":
 		topics, err = c.client.GetNewTopics()
 	case "top":
-		topics, err = c.client.GetTopTopics("weekly")
+		topics, err = c.client.GetTopTopics(c.period)
 	default:
-		topics, err = c.client.GetLatestTopics()
+		topics, err = c.client.GetLatestTopicsOrdered(c.order)
 	}
 
 	if err != nil {
@@ -122,6 +174,7 @@ func (c *CLI) loadTopics() {
 	}
 
 	c.topics = topics.TopicList.Topics
+	c.sortTopics(c.topics)
 	c.moreURL = topics.TopicList.MoreTopicsURL
 	for _, u := range topics.Users {
 		c.users[u.ID] = u.Username
@@ -169,7 +222,7 @@ func (c *CLI) cmdList(args []string) {
 		}
 	}
 
-	fmt.Printf("Topics (%s):\n", c.filter)
+	fmt.Printf("Topics (%s):\n", c.listName())
 	fmt.Println(strings.Repeat("-", 80))
 
 	for i, topic := range c.topics {
@@ -222,10 +275,15 @@ func (c *CLI) cmdOpen(args []string) {
 		topicID = c.topics[idx-1].ID
 	}
 
+	c.openTopic(topicID)
+}
+
+// openTopic 加载并进入指定话题
+func (c *CLI) openTopic(topicID int) bool {
 	detail, err := c.client.GetTopic(topicID)
 	if err != nil {
 		fmt.Printf("Error loading topic: %v\n", err)
-		return
+		return false
 	}
 
 	c.currentTopic = detail
@@ -235,6 +293,10 @@ func (c *CLI) cmdOpen(args []string) {
 
 	fmt.Printf("Opened: %s\n", detail.Title)
 	fmt.Printf("Total posts: %d\n", detail.PostsCount)
+	if detail.NotificationLevel() != client.NotificationRegular {
+		fmt.Printf("Notification: %s\n", notificationLevelName(detail.NotificationLevel()))
+	}
+	return true
 }
 
 func (c *CLI) cmdCD(args []string) {
@@ -324,22 +386,52 @@ func (c *CLI) cmdView(args []string) {
 	}
 }
 
+// renderOptions 只有输出到终端时才做语法高亮，重定向到文件时保持纯文本
+func (c *CLI) renderOptions() render.Options {
+	return render.Options{
+		Highlight: c.config.UI.HighlightEnabled() && isTerminal(os.Stdout),
+		CodeStyle: c.config.UI.CodeStyle,
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 func (c *CLI) displayPost(post client.Post) {
 	fmt.Println(strings.Repeat("=", 80))
 	fmt.Printf("Floor #%d | Author: @%s | Time: %s\n", post.PostNumber, post.Username, post.CreatedAt)
 	fmt.Println(strings.Repeat("-", 80))
 
-	content := htmlToText(post.Cooked)
+	content := render.Plain(render.Parse(post.Cooked), c.renderOptions())
 	fmt.Println(content)
 
+	for _, poll := range post.Polls {
+		fmt.Println()
+		displayPoll(post, poll)
+	}
+
 	fmt.Println(strings.Repeat("=", 80))
 
 	// Check if liked
 	for _, action := range post.ActionsSummary {
-		if action.ID == 2 && action.Acted {
+		if action.ID == client.PostActionLike && action.Acted {
 			fmt.Println("Status: Liked")
 		}
 	}
+
+	if len(post.Reactions) > 0 {
+		var parts []string
+		for _, r := range post.Reactions {
+			part := fmt.Sprintf("%s %d", emoji.Render(r.ID), r.Count)
+			if post.CurrentUserReaction != nil && post.CurrentUserReaction.ID == r.ID {
+				part = "[" + part + "]"
+			}
+			parts = append(parts, part)
+		}
+		fmt.Printf("Reactions: %s\n", strings.Join(parts, "  "))
+	}
 }
 
 func (c *CLI) cmdMore() {
@@ -363,6 +455,8 @@ func (c *CLI) cmdMore() {
 			return
 		}
 
+		// 只排序新加载的一页，已列出的话题编号保持不变
+		c.sortTopics(topics.TopicList.Topics)
 		c.topics = append(c.topics, topics.TopicList.Topics...)
 		c.moreURL = topics.TopicList.MoreTopicsURL
 		for _, u := range topics.Users {
@@ -402,8 +496,40 @@ func (c *CLI) cmdReply() {
 		return
 	}
 
-	fmt.Println("Enter your reply (type 'END' on a new line to finish, 'CANCEL' to cancel):")
+	draftKey := client.TopicDraftKey(c.currentTopic.ID)
+	draftSeq := 0
 	var lines []string
+
+	// 自动恢复上次未发送的草稿
+	if draft, err := c.client.GetDraft(draftKey); err == nil && draft != nil {
+		draftSeq = draft.Sequence
+		if draft.Reply != "" {
+			fmt.Println("Restored draft:")
+			fmt.Println(draft.Reply)
+			fmt.Println(strings.Repeat("-", 80))
+			lines = strings.Split(draft.Reply, "\n")
+		}
+	}
+
+	saved := strings.TrimSpace(strings.Join(lines, "\n"))
+	lastSave := time.Now()
+	saveDraft := func() {
+		content := strings.TrimSpace(strings.Join(lines, "\n"))
+		if content == "" || content == saved {
+			return
+		}
+		seq, err := c.client.SaveDraft(draftKey, content, 0, draftSeq)
+		if err != nil {
+			fmt.Printf("(draft not saved: %v)\n", err)
+			return
+		}
+		draftSeq = seq
+		saved = content
+		lastSave = time.Now()
+	}
+
+	fmt.Println("Enter your reply (type 'END' on a new line to finish, 'CANCEL' to cancel and keep a draft,")
+	fmt.Println("'CLEAR' to discard the draft, 'ATTACH <path>' to upload a file):")
 	for {
 		line, _ := c.reader.ReadString('\n')
 		line = strings.TrimRight(line, "\n")
@@ -411,10 +537,35 @@ func (c *CLI) cmdReply() {
 			break
 		}
 		if line == "CANCEL" {
-			fmt.Println("Reply cancelled")
+			saveDraft()
+			fmt.Println("Reply cancelled, draft saved")
 			return
 		}
+		if line == "CLEAR" {
+			lines = nil
+			if err := c.client.DeleteDraft(draftKey, draftSeq); err != nil {
+				fmt.Printf("Error deleting draft: %v\n", err)
+			}
+			saved = ""
+			fmt.Println("Draft discarded")
+			continue
+		}
+		if strings.HasPrefix(line, "ATTACH ") {
+			path := strings.TrimSpace(strings.TrimPrefix(line, "ATTACH "))
+			upload, err := c.client.UploadFile(path)
+			if err != nil {
+				fmt.Printf("Error uploading %s: %v\n", path, err)
+				continue
+			}
+			fmt.Printf("Attached: %s\n", upload.Markdown())
+			lines = append(lines, upload.Markdown())
+			continue
+		}
 		lines = append(lines, line)
+
+		if time.Since(lastSave) >= draftSaveInterval {
+			saveDraft()
+		}
 	}
 
 	content := strings.Join(lines, "\n")
@@ -427,10 +578,131 @@ func (c *CLI) cmdReply() {
 
 	err := c.client.CreatePost(c.currentTopic.ID, content, 0)
 	if err != nil {
+		saveDraft()
+		fmt.Printf("Error posting reply: %v\n", err)
+		fmt.Println("Your reply was kept as a draft")
+		return
+	}
+
+	c.client.DeleteDraft(draftKey, draftSeq)
+	fmt.Println("Reply posted successfully!")
+}
+
+// cmdReplyEditor 在 $EDITOR 中编写回复，预填服务器上的草稿
+func (c *CLI) cmdReplyEditor() {
+	if c.currentTopic == nil {
+		fmt.Println("No topic opened")
+		return
+	}
+
+	draftSeq := 0
+	initial := ""
+	if draft, err := c.client.GetDraft(client.TopicDraftKey(c.currentTopic.ID)); err == nil && draft != nil {
+		draftSeq = draft.Sequence
+		initial = draft.Reply
+	}
+
+	c.composeInEditor(initial, 0, draftSeq)
+}
+
+// cmdQuote 引用指定楼层并在 $EDITOR 中回复
+func (c *CLI) cmdQuote(args []string) {
+	if c.currentTopic == nil {
+		fmt.Println("No topic opened")
+		return
+	}
+
+	if len(args) == 0 {
+		fmt.Println("Usage: quote <floor_number>")
+		return
+	}
+
+	floor, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Printf("Invalid floor number: %s\n", args[0])
+		return
+	}
+
+	var targetPost *client.Post
+	for _, post := range c.posts {
+		if post.PostNumber == floor {
+			targetPost = &post
+			break
+		}
+	}
+
+	if targetPost == nil {
+		fmt.Printf("Floor %d not loaded yet\n", floor)
+		return
+	}
+
+	text := targetPost.Raw
+	if text == "" {
+		text = render.Markdown(render.Parse(targetPost.Cooked))
+	}
+
+	draftSeq := 0
+	if draft, err := c.client.GetDraft(client.TopicDraftKey(c.currentTopic.ID)); err == nil && draft != nil {
+		draftSeq = draft.Sequence
+	}
+
+	quote := client.QuoteMarkdown(targetPost.Username, targetPost.PostNumber, c.currentTopic.ID, text)
+	c.composeInEditor(quote, floor, draftSeq)
+}
+
+// composeInEditor 打开编辑器，预览后确认发送；取消时保存为草稿
+func (c *CLI) composeInEditor(initial string, replyTo int, draftSeq int) {
+	draftKey := client.TopicDraftKey(c.currentTopic.ID)
+	content := initial
+
+	for {
+		fmt.Printf("Opening %s...\n", editor.Name())
+		edited, err := editor.Edit(content)
+		if err != nil {
+			fmt.Printf("Error running editor: %v\n", err)
+			return
+		}
+		content = edited
+
+		if content == "" {
+			fmt.Println("Empty reply, cancelled")
+			return
+		}
+
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Println(content)
+		fmt.Println(strings.Repeat("-", 80))
+		if replyTo > 0 {
+			fmt.Printf("Post this reply to floor #%d? [y/N/e(dit)] ", replyTo)
+		} else {
+			fmt.Print("Post this reply? [y/N/e(dit)] ")
+		}
+
+		answer, _ := c.reader.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer == "e" || answer == "edit" {
+			continue
+		}
+		if answer == "y" || answer == "yes" {
+			break
+		}
+
+		if _, err := c.client.SaveDraft(draftKey, content, replyTo, draftSeq); err != nil {
+			fmt.Printf("Reply cancelled, draft not saved: %v\n", err)
+		} else {
+			fmt.Println("Reply cancelled, draft saved")
+		}
+		return
+	}
+
+	if err := c.client.CreatePost(c.currentTopic.ID, content, replyTo); err != nil {
+		c.client.SaveDraft(draftKey, content, replyTo, draftSeq)
 		fmt.Printf("Error posting reply: %v\n", err)
+		fmt.Println("Your reply was kept as a draft")
 		return
 	}
 
+	c.client.DeleteDraft(draftKey, draftSeq)
 	fmt.Println("Reply posted successfully!")
 }
 
@@ -468,7 +740,7 @@ func (c *CLI) cmdLike(args []string) {
 	// Check if already liked
 	isLiked := false
 	for _, action := range targetPost.ActionsSummary {
-		if action.ID == 2 && action.Acted {
+		if action.ID == client.PostActionLike && action.Acted {
 			isLiked = true
 			break
 		}
@@ -491,6 +763,564 @@ func (c *CLI) cmdLike(args []string) {
 	}
 }
 
+func (c *CLI) cmdReact(args []string) {
+	if len(args) < 2 {
+		reactions, _ := c.client.GetAvailableReactions()
+		fmt.Println("Usage: react <floor_number> <reaction>")
+		fmt.Print("Available reactions:")
+		for _, r := range reactions {
+			fmt.Printf("  %s %s", emoji.Render(r), r)
+		}
+		fmt.Println()
+		return
+	}
+
+	if c.currentTopic == nil {
+		fmt.Println("No topic opened")
+		return
+	}
+
+	floor, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Printf("Invalid floor number: %s\n", args[0])
+		return
+	}
+
+	idx := -1
+	for i, post := range c.posts {
+		if post.PostNumber == floor {
+			idx = i
+			break
+		}
+	}
+
+	if idx < 0 {
+		fmt.Printf("Floor %d not loaded yet\n", floor)
+		return
+	}
+
+	reaction := strings.Trim(args[1], ":")
+	post, err := c.client.ToggleReaction(c.posts[idx].ID, reaction)
+	if err != nil {
+		fmt.Printf("Error toggling reaction: %v\n", err)
+		return
+	}
+	c.posts[idx] = *post
+
+	if post.CurrentUserReaction != nil && post.CurrentUserReaction.ID == reaction {
+		fmt.Printf("Reacted %s to floor #%d\n", emoji.Render(reaction), floor)
+	} else {
+		fmt.Printf("Removed %s from floor #%d\n", emoji.Render(reaction), floor)
+	}
+}
+
+func displayPoll(post client.Post, poll client.Poll) {
+	voted := post.PollsVotes[poll.Name]
+	showResults := poll.CanSeeResults(len(voted) > 0)
+
+	status := "open"
+	if !poll.IsOpen() {
+		status = "closed"
+	}
+	kind := "single choice"
+	if poll.IsMultiple() {
+		kind = fmt.Sprintf("multiple choice, %d-%d", poll.Min, poll.Max)
+	}
+	fmt.Printf("Poll [%s] (%s, %s, %d voters)\n", poll.Name, kind, status, poll.Voters)
+
+	total := poll.TotalVotes()
+	for i, option := range poll.Options {
+		mark := " "
+		for _, id := range voted {
+			if id == option.ID {
+				mark = "*"
+			}
+		}
+		text := strings.TrimSpace(render.Plain(render.Parse(option.HTML), render.Options{}))
+		if showResults {
+			percent := 0.0
+			if total > 0 {
+				percent = float64(option.Votes) * 100 / float64(total)
+			}
+			fmt.Printf(" %s%2d. %-40s %4d votes (%3.0f%%)\n", mark, i+1, text, option.Votes, percent)
+		} else {
+			fmt.Printf(" %s%2d. %s\n", mark, i+1, text)
+		}
+	}
+	if !showResults {
+		fmt.Println("     (results are shown after voting)")
+	}
+}
+
+// findPollPost 解析 "<floor> [poll_name]" 参数，返回帖子索引、投票和剩余参数
+func (c *CLI) findPollPost(args []string) (int, client.Poll, []string, bool) {
+	floor, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Printf("Invalid floor number: %s\n", args[0])
+		return 0, client.Poll{}, nil, false
+	}
+
+	idx := -1
+	for i, post := range c.posts {
+		if post.PostNumber == floor {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		fmt.Printf("Floor %d not loaded yet\n", floor)
+		return 0, client.Poll{}, nil, false
+	}
+
+	post := c.posts[idx]
+	if len(post.Polls) == 0 {
+		fmt.Printf("Floor %d has no poll\n", floor)
+		return 0, client.Poll{}, nil, false
+	}
+
+	rest := args[1:]
+	poll := post.Polls[0]
+	if len(rest) > 0 {
+		if _, err := strconv.Atoi(rest[0]); err != nil {
+			found := false
+			for _, p := range post.Polls {
+				if p.Name == rest[0] {
+					poll = p
+					found = true
+				}
+			}
+			if !found {
+				fmt.Printf("Poll not found: %s\n", rest[0])
+				return 0, client.Poll{}, nil, false
+			}
+			rest = rest[1:]
+		}
+	}
+
+	return idx, poll, rest, true
+}
+
+func (c *CLI) updatePoll(idx int, poll client.Poll, votes []string) {
+	post := &c.posts[idx]
+	for i := range post.Polls {
+		if post.Polls[i].Name == poll.Name {
+			post.Polls[i] = poll
+		}
+	}
+	if post.PollsVotes == nil {
+		post.PollsVotes = make(map[string][]string)
+	}
+	post.PollsVotes[poll.Name] = votes
+}
+
+func (c *CLI) cmdVote(args []string) {
+	if c.currentTopic == nil {
+		fmt.Println("No topic opened")
+		return
+	}
+
+	if len(args) < 2 {
+		fmt.Println("Usage: vote <floor_number> [poll_name] <option_number>...")
+		return
+	}
+
+	idx, poll, rest, ok := c.findPollPost(args)
+	if !ok {
+		return
+	}
+
+	if len(rest) == 0 {
+		fmt.Println("Usage: vote <floor_number> [poll_name] <option_number>...")
+		return
+	}
+	if len(rest) > 1 && !poll.IsMultiple() {
+		fmt.Println("This poll only allows a single choice")
+		return
+	}
+
+	var options []string
+	for _, arg := range rest {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > len(poll.Options) {
+			fmt.Printf("Invalid option number: %s\n", arg)
+			return
+		}
+		options = append(options, poll.Options[n-1].ID)
+	}
+
+	updated, votes, err := c.client.VotePoll(c.posts[idx].ID, poll.Name, options)
+	if err != nil {
+		fmt.Printf("Error voting: %v\n", err)
+		return
+	}
+
+	c.updatePoll(idx, *updated, votes)
+	fmt.Println("Vote recorded!")
+	displayPoll(c.posts[idx], *updated)
+}
+
+func (c *CLI) cmdUnvote(args []string) {
+	if c.currentTopic == nil {
+		fmt.Println("No topic opened")
+		return
+	}
+
+	if len(args) == 0 {
+		fmt.Println("Usage: unvote <floor_number> [poll_name]")
+		return
+	}
+
+	idx, poll, _, ok := c.findPollPost(args)
+	if !ok {
+		return
+	}
+
+	updated, err := c.client.RemovePollVote(c.posts[idx].ID, poll.Name)
+	if err != nil {
+		fmt.Printf("Error removing vote: %v\n", err)
+		return
+	}
+
+	c.updatePoll(idx, *updated, nil)
+	fmt.Println("Vote removed")
+}
+
+// cmdImg 使用终端图形协议逐张显示指定楼层的图片
+func (c *CLI) cmdImg(args []string) {
+	if c.currentTopic == nil {
+		fmt.Println("No topic opened")
+		return
+	}
+
+	if len(args) == 0 {
+		fmt.Println("Usage: img <floor_number>")
+		return
+	}
+
+	floor, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Printf("Invalid floor number: %s\n", args[0])
+		return
+	}
+
+	var targetPost *client.Post
+	for _, post := range c.posts {
+		if post.PostNumber == floor {
+			targetPost = &post
+			break
+		}
+	}
+
+	if targetPost == nil {
+		fmt.Printf("Floor %d not loaded yet\n", floor)
+		return
+	}
+
+	images := render.Parse(targetPost.Cooked).Images()
+	if len(images) == 0 {
+		fmt.Printf("No images in floor #%d\n", floor)
+		return
+	}
+
+	protocol, err := termimg.ParseProtocol(c.config.UI.ImageProtocol)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	cache := termimg.NewCache()
+	viewer := &termimg.Viewer{
+		Protocol: protocol,
+		Help:     "n/space: next | p: previous | q: quit",
+	}
+	for _, img := range images {
+		url := c.client.ResolveURL(img.URL)
+		title := img.Text
+		if title == "" {
+			title = url
+		}
+		viewer.Images = append(viewer.Images, termimg.ViewerImage{
+			Title: title,
+			Load: func() (image.Image, error) {
+				data, err := cache.Load(url, c.client.DownloadImage)
+				if err != nil {
+					return nil, err
+				}
+				return termimg.Decode(data)
+			},
+		})
+	}
+
+	if err := viewer.Run(); err != nil {
+		fmt.Printf("Error displaying images: %v\n", err)
+	}
+}
+
+func (c *CLI) cmdUpload(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: upload <path>")
+		return
+	}
+
+	path := strings.Join(args, " ")
+	upload, err := c.client.UploadFile(path)
+	if err != nil {
+		fmt.Printf("Error uploading %s: %v\n", path, err)
+		return
+	}
+
+	fmt.Println("Uploaded! Paste this into your reply:")
+	fmt.Println(upload.Markdown())
+}
+
+func (c *CLI) cmdFlag(args []string) {
+	if c.currentTopic == nil {
+		fmt.Println("No topic opened")
+		return
+	}
+
+	if len(args) < 2 {
+		fmt.Println("Usage: flag <floor_number> <type> [message]")
+		fmt.Print("Types:")
+		for _, t := range client.FlagTypes {
+			fmt.Printf(" %s", t.Name)
+		}
+		fmt.Println()
+		return
+	}
+
+	floor, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Printf("Invalid floor number: %s\n", args[0])
+		return
+	}
+
+	var targetPost *client.Post
+	for i := range c.posts {
+		if c.posts[i].PostNumber == floor {
+			targetPost = &c.posts[i]
+			break
+		}
+	}
+
+	if targetPost == nil {
+		fmt.Printf("Floor %d not loaded yet\n", floor)
+		return
+	}
+
+	flagType, err := client.ParseFlagType(args[1])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	message := strings.Join(args[2:], " ")
+	if flagType.NeedMessage && message == "" {
+		fmt.Print("Message to moderators: ")
+		message, _ = c.reader.ReadString('\n')
+		message = strings.TrimSpace(message)
+		if message == "" {
+			fmt.Println("Empty message, cancelled")
+			return
+		}
+	}
+
+	fmt.Printf("Flag floor #%d by @%s as %s? (y/N): ", floor, targetPost.Username, flagType.Name)
+	confirm, _ := c.reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(confirm)) != "y" {
+		fmt.Println("Flag cancelled")
+		return
+	}
+
+	if err := c.client.FlagPost(targetPost.ID, flagType, message); err != nil {
+		fmt.Printf("Error flagging post: %v\n", err)
+		return
+	}
+
+	fmt.Println("Post flagged, thanks for reporting")
+}
+
+func (c *CLI) cmdWatch(args []string) {
+	// watch category <id> [level]
+	if len(args) > 0 && args[0] == "category" {
+		if len(args) < 2 {
+			fmt.Println("Usage: watch category <category_id> [muted|normal|tracking|watching|first]")
+			return
+		}
+		categoryID, err := strconv.Atoi(args[1])
+		if err != nil {
+			fmt.Printf("Invalid category id: %s\n", args[1])
+			return
+		}
+		level := client.NotificationWatching
+		if len(args) > 2 {
+			level, err = client.ParseNotificationLevel(args[2])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+		c.setCategoryNotificationLevel(categoryID, level)
+		return
+	}
+
+	if c.currentTopic == nil {
+		fmt.Println("No topic opened")
+		return
+	}
+
+	if len(args) == 0 {
+		fmt.Printf("Current notification level: %s\n", notificationLevelName(c.currentTopic.NotificationLevel()))
+		fmt.Println("Usage: watch [muted|normal|tracking|watching]")
+		return
+	}
+
+	level, err := client.ParseNotificationLevel(args[0])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	c.setTopicNotificationLevel(level)
+}
+
+func (c *CLI) cmdMute(args []string) {
+	// mute category <id>
+	if len(args) > 0 && args[0] == "category" {
+		if len(args) < 2 {
+			fmt.Println("Usage: mute category <category_id>")
+			return
+		}
+		categoryID, err := strconv.Atoi(args[1])
+		if err != nil {
+			fmt.Printf("Invalid category id: %s\n", args[1])
+			return
+		}
+		c.setCategoryNotificationLevel(categoryID, client.NotificationMuted)
+		return
+	}
+
+	if c.currentTopic == nil {
+		fmt.Println("No topic opened")
+		return
+	}
+
+	c.setTopicNotificationLevel(client.NotificationMuted)
+}
+
+func (c *CLI) setTopicNotificationLevel(level client.NotificationLevel) {
+	err := c.client.SetTopicNotificationLevel(c.currentTopic.ID, level)
+	if err != nil {
+		fmt.Printf("Error setting notification level: %v\n", err)
+		return
+	}
+	c.currentTopic.SetNotificationLevel(level)
+
+	// 静音的话题从列表中移除
+	if level == client.NotificationMuted {
+		var topics []client.Topic
+		for _, topic := range c.topics {
+			if topic.ID != c.currentTopic.ID {
+				topics = append(topics, topic)
+			}
+		}
+		c.topics = topics
+	}
+
+	fmt.Printf("Topic #%d notification level: %s\n", c.currentTopic.ID, notificationLevelName(level))
+}
+
+func (c *CLI) setCategoryNotificationLevel(categoryID int, level client.NotificationLevel) {
+	err := c.client.SetCategoryNotificationLevel(categoryID, level)
+	if err != nil {
+		fmt.Printf("Error setting notification level: %v\n", err)
+		return
+	}
+	fmt.Printf("Category #%d notification level: %s\n", categoryID, notificationLevelName(level))
+}
+
+func notificationLevelName(level client.NotificationLevel) string {
+	switch level {
+	case client.NotificationMuted:
+		return "muted"
+	case client.NotificationRegular:
+		return "normal"
+	case client.NotificationTracking:
+		return "tracking"
+	case client.NotificationWatching:
+		return "watching"
+	case client.NotificationWatchingFirstPost:
+		return "watching first post"
+	default:
+		return strconv.Itoa(int(level))
+	}
+}
+
+func (c *CLI) cmdWhois(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: whois <username>")
+		return
+	}
+
+	username := strings.TrimPrefix(args[0], "@")
+
+	profile, err := c.client.GetUserProfile(username)
+	if err != nil {
+		fmt.Printf("Error loading user: %v\n", err)
+		return
+	}
+
+	fmt.Println(strings.Repeat("=", 80))
+	fmt.Printf("@%s", profile.Username)
+	if profile.Name != "" {
+		fmt.Printf(" (%s)", profile.Name)
+	}
+	fmt.Printf(" | Trust level: %d", profile.TrustLevel)
+	if profile.Admin {
+		fmt.Print(" | admin")
+	} else if profile.Moderator {
+		fmt.Print(" | moderator")
+	}
+	fmt.Println()
+	if profile.Title != "" {
+		fmt.Printf("Title:     %s\n", profile.Title)
+	}
+	fmt.Printf("Joined:    %s\n", profile.CreatedAt)
+	fmt.Printf("Last seen: %s\n", profile.LastSeenAt)
+	if profile.Location != "" {
+		fmt.Printf("Location:  %s\n", profile.Location)
+	}
+	if profile.Website != "" {
+		fmt.Printf("Website:   %s\n", profile.Website)
+	}
+	if profile.BioCooked != "" {
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Println(render.Plain(render.Parse(profile.BioCooked), render.Options{}))
+	}
+
+	if summary, err := c.client.GetUserSummary(username); err == nil {
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Printf("Topics: %d | Posts: %d | Likes received: %d | Likes given: %d | Days visited: %d\n",
+			summary.TopicCount, summary.PostCount, summary.LikesReceived, summary.LikesGiven, summary.DaysVisited)
+	}
+
+	actions, err := c.client.GetUserActions(username, client.UserActionFilterPosts, 0)
+	if err == nil && len(actions) > 0 {
+		fmt.Println(strings.Repeat("-", 80))
+		fmt.Println("Recent activity:")
+		for i, action := range actions {
+			if i >= 10 {
+				break
+			}
+			title := action.Title
+			if len(title) > 50 {
+				title = title[:47] + "..."
+			}
+			fmt.Printf("  [Topic #%d, Floor #%d] %s\n", action.TopicID, action.PostNumber, title)
+		}
+	}
+	fmt.Println(strings.Repeat("=", 80))
+}
+
 func (c *CLI) cmdJump(args []string) {
 	if c.currentTopic == nil {
 		fmt.Println("No topic opened")
@@ -521,11 +1351,115 @@ func (c *CLI) cmdBrowser() {
 		return
 	}
 
-	url := fmt.Sprintf("https://linux.do/t/%d", c.currentTopic.ID)
-	fmt.Printf("Opening in browser: %s\n", url)
+	openURL(c.client.TopicURL(c.currentTopic.ID, 0))
+}
+
+// cmdLinks 列出指定楼层（默认首楼）中的链接，供 follow 命令使用
+func (c *CLI) cmdLinks(args []string) {
+	if c.currentTopic == nil {
+		fmt.Println("No topic opened")
+		return
+	}
 
-	// Try to open in browser (simple approach)
-	fmt.Println("(Browser opening not implemented in CLI mode)")
+	floor := 1
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > c.currentTopic.PostsCount {
+			fmt.Printf("Invalid floor number: %s\n", args[0])
+			return
+		}
+		floor = n
+	}
+
+	post, err := c.postByFloor(floor)
+	if err != nil {
+		fmt.Printf("Error loading post: %v\n", err)
+		return
+	}
+
+	c.links = render.Parse(post.Cooked).Links()
+	if len(c.links) == 0 {
+		fmt.Printf("No links in floor #%d\n", floor)
+		return
+	}
+
+	fmt.Printf("Links in floor #%d:\n\n", floor)
+	for i, link := range c.links {
+		tag := ""
+		if _, _, ok := c.client.TopicLink(link.URL); ok {
+			tag = "[topic] "
+		}
+		fmt.Printf("%3d. %s%s\n", i+1, tag, link.Text)
+		if link.Text != link.URL {
+			fmt.Printf("     %s\n", c.client.ResolveURL(link.URL))
+		}
+	}
+	fmt.Println("\nUse 'follow <n>' to open a link")
+}
+
+// cmdFollow 打开 links 列出的链接：站内话题直接进入，其他链接在浏览器中打开
+func (c *CLI) cmdFollow(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: follow <n>")
+		return
+	}
+	if len(c.links) == 0 {
+		fmt.Println("No links listed. Use 'links [floor]' first")
+		return
+	}
+
+	idx, err := strconv.Atoi(args[0])
+	if err != nil || idx < 1 || idx > len(c.links) {
+		fmt.Printf("Invalid link number: %s\n", args[0])
+		return
+	}
+	link := c.links[idx-1]
+
+	topicID, floor, ok := c.client.TopicLink(link.URL)
+	if !ok {
+		openURL(c.client.ResolveURL(link.URL))
+		return
+	}
+
+	if c.currentTopic == nil || c.currentTopic.ID != topicID {
+		if !c.openTopic(topicID) {
+			return
+		}
+		c.isSearchMode = false
+		c.links = nil
+	}
+	if floor > 1 {
+		c.cmdView([]string{strconv.Itoa(floor)})
+	}
+}
+
+// postByFloor 返回指定楼层的帖子，未加载时从服务器获取
+func (c *CLI) postByFloor(floor int) (*client.Post, error) {
+	for i := range c.posts {
+		if c.posts[i].PostNumber == floor {
+			return &c.posts[i], nil
+		}
+	}
+
+	if floor < 1 || floor > len(c.allPostIDs) {
+		return nil, fmt.Errorf("floor %d out of range", floor)
+	}
+	posts, err := c.client.GetPostsByIDs(c.currentTopic.ID, c.allPostIDs[floor-1:floor])
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, fmt.Errorf("floor %d not found", floor)
+	}
+	return &posts[0], nil
+}
+
+// openURL 在系统默认浏览器中打开链接，失败时只打印地址
+func openURL(url string) {
+	fmt.Printf("Opening in browser: %s\n", url)
+	if err := browser.Open(url); err != nil {
+		fmt.Printf("Error opening browser: %v\n", err)
+	}
 }
 
 func (c *CLI) cmdFilter(args []string) {
@@ -551,11 +1485,94 @@ func (c *CLI) cmdFilter(args []string) {
 		return
 	}
 
+	// filter top <period>
+	if filter == "top" && len(args) > 1 {
+		if !client.ValidTopPeriod(args[1]) {
+			fmt.Printf("Invalid period: %s\n", args[1])
+			fmt.Printf("Available periods: %s\n", strings.Join(client.TopPeriods, ", "))
+			return
+		}
+		c.period = args[1]
+	}
+
 	c.filter = filter
 	c.topics = nil
 	c.moreURL = ""
 	c.loadTopics()
-	fmt.Printf("Switched to %s filter\n", c.filter)
+	fmt.Printf("Switched to %s filter\n", c.listName())
+}
+
+// listName 返回当前列表的名称，top 列表带时间范围，排序时带排序方式
+func (c *CLI) listName() string {
+	name := c.filter
+	if c.filter == "top" {
+		name += " " + c.period
+	}
+	if c.order.By != "" {
+		name += ", sorted by " + c.order.String()
+	}
+	return name
+}
+
+// serverSorted latest 列表由服务器排序，其他列表在本地排序已加载的话题
+func (c *CLI) serverSorted() bool {
+	return c.filter == "latest"
+}
+
+func (c *CLI) sortTopics(topics []client.Topic) {
+	if !c.serverSorted() {
+		client.SortTopics(topics, c.order)
+	}
+}
+
+func (c *CLI) cmdPeriod(args []string) {
+	if len(args) == 0 {
+		fmt.Printf("Current period: %s\n", c.period)
+		fmt.Printf("Available periods: %s\n", strings.Join(client.TopPeriods, ", "))
+		return
+	}
+	if !client.ValidTopPeriod(args[0]) {
+		fmt.Printf("Invalid period: %s\n", args[0])
+		fmt.Printf("Available periods: %s\n", strings.Join(client.TopPeriods, ", "))
+		return
+	}
+
+	c.period = args[0]
+	c.filter = "top"
+	c.topics = nil
+	c.moreURL = ""
+	c.loadTopics()
+	fmt.Printf("Switched to %s filter\n", c.listName())
+}
+
+func (c *CLI) cmdSort(args []string) {
+	if len(args) == 0 {
+		fmt.Printf("Current order: %s\n", c.order)
+		fmt.Printf("Available orders: default, %s (append asc or desc)\n", strings.Join(client.TopicOrders, ", "))
+		return
+	}
+
+	direction := ""
+	if len(args) > 1 {
+		direction = args[1]
+	}
+	order, err := client.ParseTopicOrder(args[0], direction)
+	if err != nil {
+		fmt.Printf("Invalid order: %s\n", strings.Join(args, " "))
+		fmt.Printf("Available orders: default, %s (append asc or desc)\n", strings.Join(client.TopicOrders, ", "))
+		return
+	}
+
+	c.order = order
+	if c.serverSorted() || order.By == "" {
+		// 服务器排序，或者需要恢复服务器的默认顺序
+		c.topics = nil
+		c.moreURL = ""
+		c.loadTopics()
+	} else {
+		c.sortTopics(c.topics)
+	}
+	fmt.Printf("Sorted by %s\n", c.order)
 }
 
 func (c *CLI) cmdRefresh() {
@@ -657,11 +1674,44 @@ Reading:
 
 Interaction:
   reply           - Reply to current topic
+                    (type 'ATTACH <path>' on its own line to attach a file;
+                     drafts are saved automatically and restored next time)
+  reply -e        - Write the reply in $EDITOR, then preview and confirm
+  quote <floor>   - Quote a post and reply in $EDITOR
+  upload <path>   - Upload a file and print its markdown
+  img <floor>     - Show the images of a post in the terminal
+  links [floor]   - List the links in a post (default: first post)
+  follow <n>      - Open a listed link (topic links open in ldo,
+                    others in the browser)
   like <floor>    - Like/unlike a post
+  react <floor> <reaction>
+                  - Toggle an emoji reaction (run 'react' to list)
+  flag <floor> <type> [message]
+                  - Flag a post (off_topic, inappropriate, spam, other)
+  vote <floor> [poll] <n>...
+                  - Vote for poll option(s) in a post
+  unvote <floor> [poll]
+                  - Remove your vote from a poll
+  watch [level]   - Show/set topic notification level
+                    (muted, normal, tracking, watching)
+  watch category <id> [level]
+                  - Set category notification level (also: first)
+  mute            - Mute current topic
+  mute category <id>
+                  - Mute a category
+  whois <user>    - Show user profile and recent activity
   browser         - Open current topic in browser
 
 Management:
   filter [name]   - Change/show filter (latest, hot, new, top)
+  filter top <period>
+                  - Show top topics for a period
+  period [name]   - Change/show top period (daily, weekly, monthly,
+                    quarterly, yearly, all)
+  sort [field] [asc|desc]
+                  - Change/show topic order (default, activity, views,
+                    likes, posts, created); latest is sorted by the
+                    server, other lists sort the loaded topics
   refresh         - Refresh current view
   clear           - Clear screen
   help / ?        - Show this help
@@ -674,53 +1724,10 @@ Examples:
   cat 10          - View floor #10
   like 5          - Like floor #5
   filter hot      - Switch to hot topics
+  filter top monthly
+                  - Switch to this month's top topics
+  sort views asc  - Sort topics by views, least viewed first
 `
 	fmt.Println(help)
 }
 
-func htmlToText(html string) string {
-	html = strings.ReplaceAll(html, "</p>", "\n\n")
-	html = strings.ReplaceAll(html, "<br>", "\n")
-	html = strings.ReplaceAll(html, "<br/>", "\n")
-	html = strings.ReplaceAll(html, "<br />", "\n")
-	html = strings.ReplaceAll(html, "</div>", "\n")
-	html = strings.ReplaceAll(html, "</li>", "\n")
-
-	html = strings.ReplaceAll(html, "<pre>", "\n```\n")
-	html = strings.ReplaceAll(html, "</pre>", "\n```\n")
-	html = strings.ReplaceAll(html, "<code>", "`")
-	html = strings.ReplaceAll(html, "</code>", "`")
-
-	html = strings.ReplaceAll(html, "<li>", "• ")
-
-	html = strings.ReplaceAll(html, "<blockquote>", "\n> ")
-	html = strings.ReplaceAll(html, "</blockquote>", "\n")
-
-	re := regexp.MustCompile(`<[^>]*>`)
-	text := re.ReplaceAllString(html, "")
-
-	text = strings.ReplaceAll(text, "&nbsp;", " ")
-	text = strings.ReplaceAll(text, "&lt;", "<")
-	text = strings.ReplaceAll(text, "&gt;", ">")
-	text = strings.ReplaceAll(text, "&amp;", "&")
-	text = strings.ReplaceAll(text, "&quot;", "\"")
-	text = strings.ReplaceAll(text, "&#39;", "'")
-
-	lines := strings.Split(text, "\n")
-	var cleaned []string
-	prevEmpty := false
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" {
-			if !prevEmpty {
-				cleaned = append(cleaned, "")
-			}
-			prevEmpty = true
-		} else {
-			cleaned = append(cleaned, line)
-			prevEmpty = false
-		}
-	}
-
-	return strings.TrimSpace(strings.Join(cleaned, "\n"))
-}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// CreateBookmark 收藏帖子，返回书签 ID（取消收藏时需要）
+func (c *Client) CreateBookmark(postID int) (int, error) {
+	payload := map[string]any{
+		"bookmarkable_id":   postID,
+		"bookmarkable_type": "Post",
+	}
+
+	jsonData, _ := json.Marshal(payload)
+	req, _ := http.NewRequest(http.MethodPost, c.baseURL+"/bookmarks.json", strings.NewReader(string(jsonData)))
+	req.Header = c.headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Origin", c.baseURL)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	c.cache.forgetPost(postID)
+
+	if resp.StatusCode == 403 {
+		return 0, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return 0, fmt.Errorf("收藏失败 (状态码 %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return 0, err
+	}
+
+	return result.ID, nil
+}
+
+// DeleteBookmark 取消收藏
+func (c *Client) DeleteBookmark(bookmarkID int) error {
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/bookmarks/%d.json", c.baseURL, bookmarkID), nil)
+	req.Header = c.headers.Clone()
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Origin", c.baseURL)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	if resp.StatusCode != 200 && resp.StatusCode != 404 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("取消收藏失败 (状态码 %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
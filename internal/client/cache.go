@@ -0,0 +1,276 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+const (
+	// postMaxAge 按 ID 获取帖子时，缓存在该时间内的帖子直接使用，不再请求
+	postMaxAge = 10 * time.Minute
+	// cacheMaxAge 超过该时间没有更新或重新验证的缓存文件在启动时删除
+	cacheMaxAge = 30 * 24 * time.Hour
+)
+
+// ErrOffline 离线模式下访问了没有缓存的内容或需要联网的操作
+var ErrOffline = errors.New("离线模式下无法访问论坛")
+
+// Cache 话题和帖子的磁盘缓存，位于系统缓存目录下的 ldo/forum
+// 话题和话题列表按 ETag 重新验证；按 ID 获取帖子的接口不支持条件请求，帖子的 updated_at
+// 也要请求后才能知道，因此帖子只按缓存时间在 postMaxAge 内视为最新。
+// 网络不可用、被 Cloudflare 拦截、被限流或服务器出错时回退到缓存；
+// 话题被删除或设为私有（404/410）时删除缓存
+type Cache struct {
+	dir string
+}
+
+// cacheEntry 缓存文件的内容，Data 为接口返回的原始 JSON
+type cacheEntry struct {
+	ETag      string          `json:"etag,omitempty"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewCache 创建缓存，目录位于系统缓存目录下的 ldo/forum，并在后台清理过期的缓存文件
+func NewCache() *Cache {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	s := &Cache{dir: filepath.Join(base, "ldo", "forum")}
+	go s.Prune(cacheMaxAge)
+	return s
+}
+
+// Prune 删除超过 maxAge 没有更新的缓存文件和中途退出遗留的临时文件，返回删除的文件数
+func (s *Cache) Prune(maxAge time.Duration) int {
+	if s == nil {
+		return 0
+	}
+	removed := 0
+	filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		stale := time.Since(info.ModTime()) > maxAge
+		// 临时文件写完会立即重命名，留下一小时以上的都是中途退出遗留的
+		tmp := filepath.Ext(path) == ".tmp" && time.Since(info.ModTime()) > time.Hour
+		if (stale || tmp) && os.Remove(path) == nil {
+			removed++
+		}
+		return nil
+	})
+	return removed
+}
+
+// Dir 返回缓存目录
+func (s *Cache) Dir() string {
+	return s.dir
+}
+
+func (s *Cache) path(kind, key string) string {
+	return filepath.Join(s.dir, kind, key+".json")
+}
+
+func (s *Cache) load(kind, key string) (*cacheEntry, bool) {
+	if s == nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(s.path(kind, key))
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || len(entry.Data) == 0 {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// store 写入缓存，先写临时文件再重命名，避免并发读取到不完整的内容；写入失败时忽略
+func (s *Cache) store(kind, key string, entry cacheEntry) {
+	if s == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	dir := filepath.Join(s.dir, kind)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), s.path(kind, key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// touch 缓存经 ETag 验证仍是最新的，更新修改时间以免被清理
+func (s *Cache) touch(kind, key string) {
+	if s == nil {
+		return
+	}
+	now := time.Now()
+	os.Chtimes(s.path(kind, key), now, now)
+}
+
+func (s *Cache) forget(kind, key string) {
+	if s == nil {
+		return
+	}
+	os.Remove(s.path(kind, key))
+}
+
+// storePosts 按 ID 缓存帖子
+func (s *Cache) storePosts(posts []Post) {
+	now := time.Now()
+	for _, post := range posts {
+		data, err := json.Marshal(post)
+		if err != nil {
+			continue
+		}
+		s.store("posts", strconv.Itoa(post.ID), cacheEntry{FetchedAt: now, Data: data})
+	}
+}
+
+// loadPost 读取缓存的帖子，maxAge 为 0 时不检查缓存时间
+func (s *Cache) loadPost(id int, maxAge time.Duration) (Post, bool) {
+	var post Post
+	entry, ok := s.load("posts", strconv.Itoa(id))
+	if !ok || (maxAge > 0 && time.Since(entry.FetchedAt) > maxAge) {
+		return post, false
+	}
+	if err := json.Unmarshal(entry.Data, &post); err != nil {
+		return post, false
+	}
+	return post, true
+}
+
+// forgetPost 帖子被点赞、投票等操作修改后删除缓存
+func (s *Cache) forgetPost(id int) {
+	s.forget("posts", strconv.Itoa(id))
+}
+
+// listKey 话题列表按请求路径缓存
+func listKey(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(sum[:16])
+}
+
+// NewOfflineClient 创建只从缓存读取的客户端，不登录也不发出任何请求
+func NewOfflineClient(baseURL, username string) *Client {
+	return &Client{
+		baseURL:  baseURL,
+		username: username,
+		headers:  http.Header{},
+		cache:    NewCache(),
+		offline:  true,
+	}
+}
+
+// Offline 是否处于离线状态：使用 --offline 启动，最近一次请求因网络错误失败，
+// 或论坛不可用（被拦截、限流、出错）而显示了缓存内容
+func (c *Client) Offline() bool {
+	return c.offline || c.unreachable.Load()
+}
+
+// do 发送请求，记录论坛是否可以访问；离线模式下直接返回 ErrOffline
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.offline {
+		return nil, ErrOffline
+	}
+	resp, err := c.client.Do(req)
+	c.unreachable.Store(err != nil)
+	return resp, err
+}
+
+// serveStale 论坛不可用时使用缓存，并标记为离线状态
+func (c *Client) serveStale(entry *cacheEntry) []byte {
+	c.unreachable.Store(true)
+	return entry.Data
+}
+
+// canServeStale 该状态码表示论坛暂时不可用（Cloudflare 拦截、限流、服务器出错），可以回退到缓存
+func canServeStale(status int) bool {
+	return status == http.StatusForbidden || status == http.StatusTooManyRequests || status >= 500
+}
+
+// cachedJSON 发送可以使用缓存的 GET 请求：带上缓存的 ETag，未修改时返回缓存内容；
+// 网络不可用、被拦截、限流或服务器出错时回退到缓存，内容已被删除（404/410）时删除缓存；
+// 离线模式下只读缓存
+func (c *Client) cachedJSON(req *http.Request, kind, key string) ([]byte, error) {
+	cached, ok := c.cache.load(kind, key)
+	if c.offline {
+		if !ok {
+			return nil, ErrOffline
+		}
+		return cached.Data, nil
+	}
+
+	if ok && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		if ok {
+			return c.serveStale(cached), nil
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+		c.cache.touch(kind, key)
+		return cached.Data, nil
+	case canServeStale(resp.StatusCode) && ok:
+		return c.serveStale(cached), nil
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusGone:
+		c.cache.forget(kind, key)
+	case resp.StatusCode == 403:
+		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("请求失败 (状态码 %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if ok {
+			return c.serveStale(cached), nil
+		}
+		return nil, err
+	}
+	c.cache.store(kind, key, cacheEntry{
+		ETag:      resp.Header.Get("ETag"),
+		FetchedAt: time.Now(),
+		Data:      bodyBytes,
+	})
+	return bodyBytes, nil
+}
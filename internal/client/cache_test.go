@@ -0,0 +1,159 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	fhttp "github.com/bogdanfinn/fhttp"
+	tls_client "github.com/bogdanfinn/tls-client"
+)
+
+// newTestClient 创建请求 httptest 服务器、缓存在临时目录中的客户端
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *Cache) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	hc, err := tls_client.NewHttpClient(tls_client.NewNoopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := &Cache{dir: t.TempDir()}
+	return &Client{
+		baseURL: srv.URL,
+		client:  hc,
+		headers: fhttp.Header{},
+		cache:   cache,
+	}, cache
+}
+
+func TestCachedJSON(t *testing.T) {
+	const cachedData = `{"cached":true}`
+	const freshData = `{"fresh":true}`
+
+	tests := []struct {
+		name        string
+		status      int
+		cached      bool
+		offline     bool
+		want        string
+		wantErr     bool
+		wantOffline bool
+		wantEntry   string // 请求后缓存中的内容，空表示没有缓存
+	}{
+		{name: "200 写入缓存", status: 200, want: freshData, wantEntry: freshData},
+		{name: "200 更新缓存", status: 200, cached: true, want: freshData, wantEntry: freshData},
+		{name: "304 使用缓存", status: 304, cached: true, want: cachedData, wantEntry: cachedData},
+		{name: "403 回退到缓存", status: 403, cached: true, want: cachedData, wantOffline: true, wantEntry: cachedData},
+		{name: "403 没有缓存", status: 403, wantErr: true},
+		{name: "429 回退到缓存", status: 429, cached: true, want: cachedData, wantOffline: true, wantEntry: cachedData},
+		{name: "502 回退到缓存", status: 502, cached: true, want: cachedData, wantOffline: true, wantEntry: cachedData},
+		{name: "500 没有缓存", status: 500, wantErr: true},
+		{name: "404 删除缓存", status: 404, cached: true, wantErr: true},
+		{name: "410 删除缓存", status: 410, cached: true, wantErr: true},
+		{name: "401 不使用缓存", status: 401, cached: true, wantErr: true, wantEntry: cachedData},
+		{name: "离线有缓存", cached: true, offline: true, want: cachedData, wantOffline: true, wantEntry: cachedData},
+		{name: "离线没有缓存", offline: true, wantErr: true, wantOffline: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			c, cache := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if tt.status == 304 && r.Header.Get("If-None-Match") != `"v1"` {
+					t.Errorf("If-None-Match = %q, want %q", r.Header.Get("If-None-Match"), `"v1"`)
+				}
+				w.Header().Set("ETag", `"v2"`)
+				w.WriteHeader(tt.status)
+				if tt.status == 200 {
+					w.Write([]byte(freshData))
+				} else {
+					w.Write([]byte("error"))
+				}
+			})
+			c.offline = tt.offline
+			if tt.cached {
+				cache.store("topics", "1", cacheEntry{ETag: `"v1"`, FetchedAt: time.Now(), Data: []byte(cachedData)})
+			}
+
+			req, _ := fhttp.NewRequest(fhttp.MethodGet, c.baseURL+"/t/1.json", nil)
+			got, err := c.cachedJSON(req, "topics", "1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("data = %q, want %q", got, tt.want)
+			}
+			if c.Offline() != tt.wantOffline {
+				t.Errorf("Offline() = %v, want %v", c.Offline(), tt.wantOffline)
+			}
+			if tt.offline {
+				if requests != 0 {
+					t.Errorf("离线模式发出了 %d 个请求", requests)
+				}
+				if !tt.cached && !errors.Is(err, ErrOffline) {
+					t.Errorf("err = %v, want ErrOffline", err)
+				}
+			}
+
+			entry, ok := cache.load("topics", "1")
+			switch {
+			case tt.wantEntry == "" && ok:
+				t.Errorf("缓存应为空，实际为 %s", entry.Data)
+			case tt.wantEntry != "" && !ok:
+				t.Errorf("缓存为空，want %s", tt.wantEntry)
+			case ok && string(entry.Data) != tt.wantEntry:
+				t.Errorf("缓存 = %s, want %s", entry.Data, tt.wantEntry)
+			}
+		})
+	}
+}
+
+func TestCachedJSONRecoversFromStale(t *testing.T) {
+	status := 503
+	c, cache := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"fresh":true}`))
+	})
+	cache.store("lists", "latest", cacheEntry{FetchedAt: time.Now(), Data: []byte(`{"cached":true}`)})
+
+	req, _ := fhttp.NewRequest(fhttp.MethodGet, c.baseURL+"/latest.json", nil)
+	if _, err := c.cachedJSON(req, "lists", "latest"); err != nil || !c.Offline() {
+		t.Fatalf("503: err = %v, Offline() = %v", err, c.Offline())
+	}
+
+	status = 200
+	req, _ = fhttp.NewRequest(fhttp.MethodGet, c.baseURL+"/latest.json", nil)
+	if _, err := c.cachedJSON(req, "lists", "latest"); err != nil || c.Offline() {
+		t.Fatalf("200: err = %v, Offline() = %v", err, c.Offline())
+	}
+}
+
+func TestCachePrune(t *testing.T) {
+	cache := &Cache{dir: t.TempDir()}
+	cache.store("topics", "old", cacheEntry{FetchedAt: time.Now(), Data: []byte(`{}`)})
+	cache.store("topics", "new", cacheEntry{FetchedAt: time.Now(), Data: []byte(`{}`)})
+	tmp := filepath.Join(cache.dir, "topics", "left.123.tmp")
+	if err := os.WriteFile(tmp, []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-cacheMaxAge - time.Hour)
+	os.Chtimes(cache.path("topics", "old"), old, old)
+	os.Chtimes(tmp, time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour))
+
+	if n := cache.Prune(cacheMaxAge); n != 2 {
+		t.Errorf("Prune() = %d, want 2", n)
+	}
+	if _, ok := cache.load("topics", "old"); ok {
+		t.Error("过期的缓存没有删除")
+	}
+	if _, ok := cache.load("topics", "new"); !ok {
+		t.Error("未过期的缓存被删除")
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// Category 论坛分类
+type Category struct {
+	ID               int        `json:"id"`
+	Name             string     `json:"name"`
+	Slug             string     `json:"slug"`
+	TopicCount       int        `json:"topic_count"`
+	ParentCategoryID int        `json:"parent_category_id"`
+	SubcategoryList  []Category `json:"subcategory_list"`
+}
+
+// GetCategories 获取所有分类，子分类展开在父分类之后，名称为 "父分类 / 子分类"
+func (c *Client) GetCategories() ([]Category, error) {
+	req, _ := http.NewRequest(http.MethodGet, c.baseURL+"/categories.json?include_subcategories=true", nil)
+	req.Header = c.headers.Clone()
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("获取分类失败 (状态码 %d)", resp.StatusCode)
+	}
+
+	var result struct {
+		CategoryList struct {
+			Categories []Category `json:"categories"`
+		} `json:"category_list"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, err
+	}
+
+	var categories []Category
+	for _, cat := range result.CategoryList.Categories {
+		subs := cat.SubcategoryList
+		cat.SubcategoryList = nil
+		categories = append(categories, cat)
+		for _, sub := range subs {
+			sub.Name = cat.Name + " / " + sub.Name
+			sub.ParentCategoryID = cat.ID
+			sub.SubcategoryList = nil
+			categories = append(categories, sub)
+		}
+	}
+	return categories, nil
+}
+
+// GetCategoryTopics 获取分类下的最新话题，由服务器按 order 排序
+func (c *Client) GetCategoryTopics(category Category, order TopicOrder) (*TopicList, error) {
+	return c.getTopics(fmt.Sprintf("/c/%s/%d/l/latest.json", url.PathEscape(category.Slug), category.ID) + order.query())
+}
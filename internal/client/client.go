@@ -7,7 +7,9 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	http "github.com/bogdanfinn/fhttp"
@@ -22,6 +24,11 @@ type Client struct {
 	csrfToken string
 	username  string
 	headers   http.Header
+	reactions []string // 站点启用的表情回应（缓存）
+
+	cache       *Cache      // 话题和帖子的磁盘缓存
+	offline     bool        // 以 --offline 启动，只读取缓存
+	unreachable atomic.Bool // 最近一次请求失败，或论坛不可用而使用了缓存
 }
 
 type TopicList struct {
@@ -43,12 +50,26 @@ type Topic struct {
 	ReplyCount   int    `json:"reply_count"`
 	PostsCount   int    `json:"posts_count"`
 	Views        int    `json:"views"`
+	LikeCount    int    `json:"like_count"`
 	CategoryID   int    `json:"category_id"`
 	Pinned       bool   `json:"pinned"`
 	Visible      bool   `json:"visible"`
 	Closed       bool   `json:"closed"`
 	Archived     bool   `json:"archived"`
 	LastPostedAt string `json:"last_posted_at"`
+	BumpedAt     string `json:"bumped_at"`
+	CreatedAt    string `json:"created_at"`
+
+	// 最后一个帖子的楼层号，分栏预览按楼层号获取最新回复
+	HighestPostNumber int `json:"highest_post_number"`
+
+	// 只有用户对话题有跟踪数据时服务器才返回，没有时为 nil
+	NotificationLevel *NotificationLevel `json:"notification_level"`
+}
+
+// Muted 话题是否被明确设置为静音
+func (t Topic) Muted() bool {
+	return t.NotificationLevel != nil && *t.NotificationLevel == NotificationMuted
 }
 
 type TopicDetail struct {
@@ -60,6 +81,23 @@ type TopicDetail struct {
 		Posts  []Post `json:"posts"`
 		Stream []int  `json:"stream"` // 所有帖子ID列表
 	} `json:"post_stream"`
+	Details struct {
+		// 匿名访问或部分话题类型不返回，为 nil
+		NotificationLevel *NotificationLevel `json:"notification_level"`
+	} `json:"details"`
+}
+
+// NotificationLevel 返回当前用户对话题的通知级别，服务器没有返回时视为普通
+func (d *TopicDetail) NotificationLevel() NotificationLevel {
+	if d.Details.NotificationLevel == nil {
+		return NotificationRegular
+	}
+	return *d.Details.NotificationLevel
+}
+
+// SetNotificationLevel 记录修改后的通知级别
+func (d *TopicDetail) SetNotificationLevel(level NotificationLevel) {
+	d.Details.NotificationLevel = &level
 }
 
 type Post struct {
@@ -70,6 +108,16 @@ type Post struct {
 	PostNumber     int             `json:"post_number"`
 	CreatedAt      string          `json:"created_at"`
 	ActionsSummary []ActionSummary `json:"actions_summary"`
+
+	Reactions           []Reaction           `json:"reactions"`
+	CurrentUserReaction *CurrentUserReaction `json:"current_user_reaction"`
+	ReactionUsersCount  int                  `json:"reaction_users_count"`
+
+	Polls      []Poll              `json:"polls"`
+	PollsVotes map[string][]string `json:"polls_votes"` // 投票名称 -> 当前用户选择的选项ID
+
+	Bookmarked bool `json:"bookmarked"`
+	BookmarkID int  `json:"bookmark_id"`
 }
 
 type SearchResult struct {
@@ -103,9 +151,19 @@ func (s *SearchResponse) GetTopicTitle(topicID int) string {
 
 type ActionSummary struct {
 	ID    int  `json:"id"`
+	Count int  `json:"count"`
 	Acted bool `json:"acted"`
 }
 
+// post_action_type_id
+const (
+	PostActionLike             = 2
+	PostActionOffTopic         = 3
+	PostActionInappropriate    = 4
+	PostActionNotifyModerators = 7
+	PostActionSpam             = 8
+)
+
 type savedCookies struct {
 	Cookies  []*http.Cookie `json:"cookies"`
 	Username string         `json:"username"`
@@ -143,6 +201,7 @@ func NewClient(baseURL, username, password string) (*Client, error) {
 		jar:      jar,
 		headers:  commonHeaders,
 		username: username,
+		cache:    NewCache(),
 	}
 
 	if err := c.loadCookies(); err == nil {
@@ -227,7 +286,7 @@ func (c *Client) warmup() error {
 	req, _ := http.NewRequest(http.MethodGet, c.baseURL+"/", nil)
 	req.Header = c.headers.Clone()
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -244,7 +303,7 @@ func (c *Client) fetchCSRF() error {
 	req.Header.Set("X-Requested-With", "XMLHttpRequest")
 	req.Header.Set("Referer", c.baseURL+"/login")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -286,7 +345,7 @@ func (c *Client) login(username, password string) error {
 	req.Header.Set("Referer", c.baseURL+"/login")
 	req.Header.Set("Accept", "application/json, text/javascript, */*; q=0.01")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -308,7 +367,12 @@ func (c *Client) login(username, password string) error {
 }
 
 func (c *Client) GetLatestTopics() (*TopicList, error) {
-	return c.getTopics("/latest.json")
+	return c.GetLatestTopicsOrdered(TopicOrder{})
+}
+
+// GetLatestTopicsOrdered 获取最新话题，由服务器按 order 排序
+func (c *Client) GetLatestTopicsOrdered(order TopicOrder) (*TopicList, error) {
+	return c.getTopics("/latest.json" + order.query())
 }
 
 func (c *Client) GetHotTopics() (*TopicList, error) {
@@ -319,8 +383,9 @@ func (c *Client) GetNewTopics() (*TopicList, error) {
 	return c.getTopics("/new.json")
 }
 
+// GetTopTopics 获取排行榜，period 为 TopPeriods 之一
 func (c *Client) GetTopTopics(period string) (*TopicList, error) {
-	return c.getTopics("/top.json?period=" + period)
+	return c.getTopics("/top.json?period=" + url.QueryEscape(period))
 }
 
 func (c *Client) getTopics(path string) (*TopicList, error) {
@@ -330,17 +395,11 @@ func (c *Client) getTopics(path string) (*TopicList, error) {
 	req.Header.Set("X-CSRF-Token", c.csrfToken)
 	req.Header.Set("X-Requested-With", "XMLHttpRequest")
 
-	resp, err := c.client.Do(req)
+	bodyBytes, err := c.cachedJSON(req, "lists", listKey(path))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 403 {
-		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
-	}
-
-	bodyBytes, _ := io.ReadAll(resp.Body)
 	var topicList TopicList
 	if err := json.Unmarshal(bodyBytes, &topicList); err != nil {
 		return nil, err
@@ -356,31 +415,98 @@ func (c *Client) GetTopic(id int) (*TopicDetail, error) {
 	req.Header.Set("X-CSRF-Token", c.csrfToken)
 	req.Header.Set("X-Requested-With", "XMLHttpRequest")
 
-	resp, err := c.client.Do(req)
+	bodyBytes, err := c.cachedJSON(req, "topics", strconv.Itoa(id))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 403 {
-		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
-	}
-
-	bodyBytes, _ := io.ReadAll(resp.Body)
 	var detail TopicDetail
 	if err := json.Unmarshal(bodyBytes, &detail); err != nil {
 		return nil, err
 	}
 
+	c.cache.storePosts(detail.PostStream.Posts)
 	return &detail, nil
 }
 
-// GetPostsByIDs 根据帖子ID列表获取帖子内容
+// GetPostByNumber 按楼层号获取单个帖子。与 GetTopic 不同，不会给话题记一次浏览，
+// 适合分栏预览这类只是看一眼的场景
+func (c *Client) GetPostByNumber(topicID, number int) (*Post, error) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/posts/by_number/%d/%d.json", c.baseURL, topicID, number), nil)
+	req.Header = c.headers.Clone()
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+
+	bodyBytes, err := c.cachedJSON(req, "floors", fmt.Sprintf("%d-%d", topicID, number))
+	if err != nil {
+		return nil, err
+	}
+
+	var post Post
+	if err := json.Unmarshal(bodyBytes, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// GetPostsByIDs 根据帖子ID列表获取帖子内容，最近缓存过的帖子不再请求。
+// 请求失败且缓存也无法补齐时，返回已有的帖子和错误，调用方不能把结果当作完整的一批
 func (c *Client) GetPostsByIDs(topicID int, postIDs []int) ([]Post, error) {
 	if len(postIDs) == 0 {
 		return nil, nil
 	}
 
+	// 离线时使用所有缓存，否则只使用 postMaxAge 内缓存的帖子
+	maxAge := postMaxAge
+	if c.offline {
+		maxAge = 0
+	}
+	found := make(map[int]Post)
+	var missing []int
+	for _, id := range postIDs {
+		if post, ok := c.cache.loadPost(id, maxAge); ok {
+			found[id] = post
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	var fetchErr error
+	if len(missing) > 0 {
+		posts, err := c.fetchPosts(topicID, missing)
+		if err != nil {
+			// 网络不可用或被拦截时用较旧的缓存补齐
+			lost := 0
+			for _, id := range missing {
+				if post, ok := c.cache.loadPost(id, 0); ok {
+					found[id] = post
+					c.unreachable.Store(true)
+				} else {
+					lost++
+				}
+			}
+			if lost > 0 {
+				fetchErr = fmt.Errorf("有 %d 条帖子获取失败: %w", lost, err)
+			}
+		}
+		c.cache.storePosts(posts)
+		for _, post := range posts {
+			found[post.ID] = post
+		}
+	}
+
+	result := make([]Post, 0, len(postIDs))
+	for _, id := range postIDs {
+		if post, ok := found[id]; ok {
+			result = append(result, post)
+		}
+	}
+	return result, fetchErr
+}
+
+// fetchPosts 从论坛获取帖子
+func (c *Client) fetchPosts(topicID int, postIDs []int) ([]Post, error) {
 	// 构建帖子ID字符串
 	var postIDStrs []string
 	for _, id := range postIDs {
@@ -396,7 +522,7 @@ func (c *Client) GetPostsByIDs(topicID int, postIDs []int) ([]Post, error) {
 	req.Header.Set("X-CSRF-Token", c.csrfToken)
 	req.Header.Set("X-Requested-With", "XMLHttpRequest")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -438,7 +564,7 @@ func (c *Client) CreatePost(topicID int, raw string, replyToPostNumber int) erro
 	req.Header.Set("Origin", c.baseURL)
 	req.Header.Set("Referer", fmt.Sprintf("%s/t/%d", c.baseURL, topicID))
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -459,7 +585,7 @@ func (c *Client) CreatePost(topicID int, raw string, replyToPostNumber int) erro
 func (c *Client) LikePost(postID int) error {
 	payload := map[string]any{
 		"id":                   postID,
-		"post_action_type_id": 2,
+		"post_action_type_id": PostActionLike,
 	}
 
 	jsonData, _ := json.Marshal(payload)
@@ -471,11 +597,12 @@ func (c *Client) LikePost(postID int) error {
 	req.Header.Set("X-Requested-With", "XMLHttpRequest")
 	req.Header.Set("Origin", c.baseURL)
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	c.cache.forgetPost(postID)
 
 	if resp.StatusCode == 403 {
 		return fmt.Errorf("被 Cloudflare 拦截 (403)")
@@ -485,18 +612,19 @@ func (c *Client) LikePost(postID int) error {
 }
 
 func (c *Client) UnlikePost(postID int) error {
-	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/post_actions/%d.json?post_action_type_id=2", c.baseURL, postID), nil)
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/post_actions/%d.json?post_action_type_id=%d", c.baseURL, postID, PostActionLike), nil)
 	req.Header = c.headers.Clone()
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("X-CSRF-Token", c.csrfToken)
 	req.Header.Set("X-Requested-With", "XMLHttpRequest")
 	req.Header.Set("Origin", c.baseURL)
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	c.cache.forgetPost(postID)
 
 	if resp.StatusCode == 403 {
 		return fmt.Errorf("被 Cloudflare 拦截 (403)")
@@ -518,17 +646,11 @@ func (c *Client) GetMoreTopics(moreURL string) (*TopicList, error) {
 	req.Header.Set("X-CSRF-Token", c.csrfToken)
 	req.Header.Set("X-Requested-With", "XMLHttpRequest")
 
-	resp, err := c.client.Do(req)
+	bodyBytes, err := c.cachedJSON(req, "lists", listKey(moreURL))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == 403 {
-		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
-	}
 
-	bodyBytes, _ := io.ReadAll(resp.Body)
 	var topicList TopicList
 	if err := json.Unmarshal(bodyBytes, &topicList); err != nil {
 		return nil, err
@@ -543,16 +665,62 @@ func (c *Client) GetUnreadTopics() (*TopicList, error) {
 
 // UserAction 表示用户的一个动作（发帖或回复）
 type UserAction struct {
-	ActionType int    `json:"action_type"` // 4=发帖, 5=回复
+	ActionType int    `json:"action_type"` // 1=点赞, 2=被赞, 4=发帖, 5=回复
 	TopicID    int    `json:"topic_id"`
+	PostID     int    `json:"post_id"`
 	PostNumber int    `json:"post_number"`
 	Username   string `json:"username"`
+	Title      string `json:"title"`
+	Excerpt    string `json:"excerpt"`
+	CategoryID int    `json:"category_id"`
+	CreatedAt  string `json:"created_at"`
 }
 
 type UserActionsResponse struct {
 	UserActions []UserAction `json:"user_actions"`
 }
 
+// 常用的 user_actions 过滤器
+const (
+	UserActionFilterLikes   = "1"
+	UserActionFilterTopics  = "4"
+	UserActionFilterReplies = "5"
+	UserActionFilterPosts   = "4,5"
+)
+
+// GetUserActions 获取指定用户的动作列表，filter 为逗号分隔的 action_type
+func (c *Client) GetUserActions(username string, filter string, offset int) ([]UserAction, error) {
+	actionsURL := fmt.Sprintf("%s/user_actions.json?offset=%d&username=%s",
+		c.baseURL, offset, url.QueryEscape(username))
+	if filter != "" {
+		actionsURL += "&filter=" + filter
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, actionsURL, nil)
+	req.Header = c.headers.Clone()
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	var result UserActionsResponse
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, err
+	}
+
+	return result.UserActions, nil
+}
+
 // GetUserRepliedTopics 获取用户已回复过的所有话题ID
 func (c *Client) GetUserRepliedTopics() (map[int]bool, error) {
 	repliedTopics := make(map[int]bool)
@@ -560,38 +728,18 @@ func (c *Client) GetUserRepliedTopics() (map[int]bool, error) {
 	limit := 30 // 每次获取30条
 
 	for {
-		url := fmt.Sprintf("%s/user_actions.json?offset=%d&username=%s&filter=4,5",
-			c.baseURL, offset, c.username)
-
-		req, _ := http.NewRequest(http.MethodGet, url, nil)
-		req.Header = c.headers.Clone()
-		req.Header.Set("Accept", "application/json")
-		req.Header.Set("X-CSRF-Token", c.csrfToken)
-		req.Header.Set("X-Requested-With", "XMLHttpRequest")
-
-		resp, err := c.client.Do(req)
+		actions, err := c.GetUserActions(c.username, UserActionFilterPosts, offset)
 		if err != nil {
 			return repliedTopics, err
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode == 403 {
-			return repliedTopics, fmt.Errorf("被 Cloudflare 拦截 (403)")
-		}
-
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		var result UserActionsResponse
-		if err := json.Unmarshal(bodyBytes, &result); err != nil {
-			return repliedTopics, err
-		}
 
 		// 没有更多数据了
-		if len(result.UserActions) == 0 {
+		if len(actions) == 0 {
 			break
 		}
 
 		// 收集已回复的话题ID
-		for _, action := range result.UserActions {
+		for _, action := range actions {
 			// action_type=5 表示回复，post_number>1 表示不是楼主帖
 			if action.ActionType == 5 && action.PostNumber > 1 {
 				repliedTopics[action.TopicID] = true
@@ -599,7 +747,7 @@ func (c *Client) GetUserRepliedTopics() (map[int]bool, error) {
 		}
 
 		// 如果返回的数量少于limit，说明已经是最后一页了
-		if len(result.UserActions) < limit {
+		if len(actions) < limit {
 			break
 		}
 
@@ -623,7 +771,7 @@ func (c *Client) Search(query string, page int) (*SearchResponse, error) {
 	req.Header.Set("X-CSRF-Token", c.csrfToken)
 	req.Header.Set("X-Requested-With", "XMLHttpRequest")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -646,3 +794,8 @@ func (c *Client) Search(query string, page int) (*SearchResponse, error) {
 
 	return &searchResp, nil
 }
+
+// QuoteMarkdown 生成 Discourse 引用格式的 Markdown
+func QuoteMarkdown(username string, postNumber int, topicID int, text string) string {
+	return fmt.Sprintf("[quote=\"%s, post:%d, topic:%d\"]\n%s\n[/quote]\n\n", username, postNumber, topicID, strings.TrimSpace(text))
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// Draft 服务器端保存的回复草稿
+type Draft struct {
+	Key               string
+	Reply             string
+	ReplyToPostNumber int
+	Sequence          int // draft_sequence，保存和删除时需要带上
+}
+
+type draftData struct {
+	Reply             string `json:"reply"`
+	Action            string `json:"action"`
+	ReplyToPostNumber int    `json:"replyToPostNumber,omitempty"`
+	ComposerTime      int64  `json:"composerTime"`
+}
+
+// TopicDraftKey 返回话题回复草稿的 key
+func TopicDraftKey(topicID int) string {
+	return fmt.Sprintf("topic_%d", topicID)
+}
+
+// GetDraft 获取草稿，没有草稿时返回 nil
+func (c *Client) GetDraft(key string) (*Draft, error) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/drafts/%s.json", c.baseURL, url.PathEscape(key)), nil)
+	req.Header = c.headers.Clone()
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	if resp.StatusCode == 404 {
+		return nil, nil
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	var result struct {
+		Draft         *string `json:"draft"`
+		DraftSequence int     `json:"draft_sequence"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, err
+	}
+
+	draft := &Draft{Key: key, Sequence: result.DraftSequence}
+	if result.Draft == nil {
+		return draft, nil
+	}
+
+	var data draftData
+	if err := json.Unmarshal([]byte(*result.Draft), &data); err != nil {
+		return nil, err
+	}
+	draft.Reply = data.Reply
+	draft.ReplyToPostNumber = data.ReplyToPostNumber
+
+	return draft, nil
+}
+
+// SaveDraft 保存草稿，返回新的 draft_sequence
+func (c *Client) SaveDraft(key string, reply string, replyToPostNumber int, sequence int) (int, error) {
+	data, _ := json.Marshal(draftData{
+		Reply:             reply,
+		Action:            "reply",
+		ReplyToPostNumber: replyToPostNumber,
+		ComposerTime:      time.Now().UnixMilli(),
+	})
+
+	formData := url.Values{}
+	formData.Set("draft_key", key)
+	formData.Set("data", string(data))
+	formData.Set("sequence", strconv.Itoa(sequence))
+
+	req, _ := http.NewRequest(http.MethodPost, c.baseURL+"/drafts.json", strings.NewReader(formData.Encode()))
+	req.Header = c.headers.Clone()
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Origin", c.baseURL)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return sequence, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return sequence, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return sequence, fmt.Errorf("保存草稿失败 (状态码 %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result struct {
+		DraftSequence int `json:"draft_sequence"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return sequence, err
+	}
+
+	return result.DraftSequence, nil
+}
+
+// DeleteDraft 删除草稿
+func (c *Client) DeleteDraft(key string, sequence int) error {
+	deleteURL := fmt.Sprintf("%s/drafts/%s.json?sequence=%d", c.baseURL, url.PathEscape(key), sequence)
+	req, _ := http.NewRequest(http.MethodDelete, deleteURL, nil)
+	req.Header = c.headers.Clone()
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Origin", c.baseURL)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	if resp.StatusCode != 200 && resp.StatusCode != 404 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("删除草稿失败 (状态码 %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
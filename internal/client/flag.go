@@ -0,0 +1,78 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// FlagType 举报类型
+type FlagType struct {
+	ID          int    // post_action_type_id
+	Name        string // 命令行使用的名称
+	Label       string
+	NeedMessage bool // 是否必须附带说明
+}
+
+// FlagTypes 支持的举报类型
+var FlagTypes = []FlagType{
+	{ID: PostActionOffTopic, Name: "off_topic", Label: "偏离主题"},
+	{ID: PostActionInappropriate, Name: "inappropriate", Label: "不当内容"},
+	{ID: PostActionSpam, Name: "spam", Label: "垃圾信息"},
+	{ID: PostActionNotifyModerators, Name: "other", Label: "其他（需说明）", NeedMessage: true},
+}
+
+// ParseFlagType 根据名称查找举报类型
+func ParseFlagType(name string) (FlagType, error) {
+	for _, t := range FlagTypes {
+		if t.Name == strings.ToLower(name) {
+			return t, nil
+		}
+	}
+	return FlagType{}, fmt.Errorf("未知的举报类型: %s", name)
+}
+
+// FlagPost 举报帖子，message 仅在需要说明的类型中使用
+func (c *Client) FlagPost(postID int, flagType FlagType, message string) error {
+	if flagType.NeedMessage && strings.TrimSpace(message) == "" {
+		return fmt.Errorf("举报类型 %s 需要填写说明", flagType.Name)
+	}
+
+	payload := map[string]any{
+		"id":                  postID,
+		"post_action_type_id": flagType.ID,
+		"flag_topic":          false,
+	}
+	if message != "" {
+		payload["message"] = message
+	}
+
+	jsonData, _ := json.Marshal(payload)
+	req, _ := http.NewRequest(http.MethodPost, c.baseURL+"/post_actions.json", strings.NewReader(string(jsonData)))
+	req.Header = c.headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Origin", c.baseURL)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("举报失败 (状态码 %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// maxImageSize 下载图片的大小上限
+const maxImageSize = 20 << 20
+
+// ResolveURL 将帖子中的相对地址（/uploads/...、//cdn.example.com/...）补全为绝对地址
+func (c *Client) ResolveURL(raw string) string {
+	switch {
+	case strings.HasPrefix(raw, "//"):
+		return "https:" + raw
+	case strings.HasPrefix(raw, "/"):
+		return c.baseURL + raw
+	}
+	return raw
+}
+
+// DownloadImage 通过已登录的会话下载图片，私有分类中的图片也能正常获取
+func (c *Client) DownloadImage(rawURL string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, c.ResolveURL(rawURL), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = c.headers.Clone()
+	// 终端只能解码 PNG/JPEG/GIF，避免服务器返回 WebP/AVIF
+	req.Header.Set("Accept", "image/png,image/jpeg,image/gif;q=0.9,*/*;q=0.5")
+	req.Header.Set("Referer", c.baseURL+"/")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("下载图片失败 (状态码 %d)", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, fmt.Errorf("图片超过 %d MB，已跳过", maxImageSize>>20)
+	}
+
+	return data, nil
+}
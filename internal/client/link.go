@@ -0,0 +1,62 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TopicLink 判断链接是否指向本站的话题，返回话题 ID 和楼层号（未指定楼层时为 0）
+// 支持 /t/slug/123、/t/slug/123/4、/t/123 等形式。slug 可能是数字（如标题为年份），
+// 因此有两段以上且第二段为数字时，第二段为话题 ID
+func (c *Client) TopicLink(rawURL string) (topicID, postNumber int, ok bool) {
+	u, err := url.Parse(c.ResolveURL(rawURL))
+	if err != nil {
+		return 0, 0, false
+	}
+	base, err := url.Parse(c.baseURL)
+	if err != nil || !strings.EqualFold(u.Hostname(), base.Hostname()) {
+		return 0, 0, false
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "t" {
+		return 0, 0, false
+	}
+	parts = parts[1:]
+	// 第一段不是数字，或第二段也是数字时，第一段为 slug
+	if _, err := strconv.Atoi(parts[0]); err != nil {
+		parts = parts[1:]
+	} else if len(parts) > 1 {
+		if _, err := strconv.Atoi(parts[1]); err == nil {
+			parts = parts[1:]
+		}
+	}
+	if len(parts) == 0 {
+		return 0, 0, false
+	}
+
+	topicID, err = strconv.Atoi(parts[0])
+	if err != nil || topicID <= 0 {
+		return 0, 0, false
+	}
+	if len(parts) > 1 {
+		postNumber, _ = strconv.Atoi(parts[1])
+	}
+	return topicID, postNumber, true
+}
+
+// TopicURL 返回话题（postNumber 大于 0 时为指定楼层）的网页地址。
+// 不知道 slug 时使用占位的 "topic"，Discourse 会跳转到正确的地址
+func (c *Client) TopicURL(topicID, postNumber int) string {
+	if postNumber > 0 {
+		return fmt.Sprintf("%s/t/topic/%d/%d", c.baseURL, topicID, postNumber)
+	}
+	return fmt.Sprintf("%s/t/topic/%d", c.baseURL, topicID)
+}
+
+// UserURL 返回用户主页的网页地址
+func (c *Client) UserURL(username string) string {
+	return fmt.Sprintf("%s/u/%s", c.baseURL, url.PathEscape(username))
+}
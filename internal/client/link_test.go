@@ -0,0 +1,46 @@
+package client
+
+import "testing"
+
+func TestTopicLink(t *testing.T) {
+	c := &Client{baseURL: "https://linux.do"}
+	tests := []struct {
+		url   string
+		topic int
+		floor int
+		ok    bool
+	}{
+		{"https://linux.do/t/some-topic/123", 123, 0, true},
+		{"https://linux.do/t/some-topic/123/4", 123, 4, true},
+		{"/t/some-topic/123/4?u=foo", 123, 4, true},
+		{"https://linux.do/t/123", 123, 0, true},
+		{"https://LINUX.DO/t/topic/123", 123, 0, true},
+		// 数字 slug：第二段为话题 ID
+		{"https://linux.do/t/2024/123", 123, 0, true},
+		{"https://linux.do/t/2024/123/5", 123, 5, true},
+		{"https://linux.do/t/some-topic", 0, 0, false},
+		{"https://linux.do/u/foo", 0, 0, false},
+		{"https://example.com/t/some-topic/123", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			topic, floor, ok := c.TopicLink(tt.url)
+			if topic != tt.topic || floor != tt.floor || ok != tt.ok {
+				t.Errorf("TopicLink = (%d, %d, %v), want (%d, %d, %v)", topic, floor, ok, tt.topic, tt.floor, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTopicURLRoundTrip(t *testing.T) {
+	c := &Client{baseURL: "https://linux.do"}
+	for _, floor := range []int{0, 1, 42} {
+		topic, got, ok := c.TopicLink(c.TopicURL(123, floor))
+		if !ok || topic != 123 || got != floor {
+			t.Errorf("TopicLink(TopicURL(123, %d)) = (%d, %d, %v)", floor, topic, got, ok)
+		}
+	}
+	if got, want := c.UserURL("a b"), "https://linux.do/u/a%20b"; got != want {
+		t.Errorf("UserURL = %q, want %q", got, want)
+	}
+}
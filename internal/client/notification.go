@@ -0,0 +1,101 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// NotificationLevel Discourse 通知级别
+type NotificationLevel int
+
+const (
+	NotificationMuted             NotificationLevel = 0 // 静音
+	NotificationRegular           NotificationLevel = 1 // 普通
+	NotificationTracking          NotificationLevel = 2 // 跟踪
+	NotificationWatching          NotificationLevel = 3 // 关注
+	NotificationWatchingFirstPost NotificationLevel = 4 // 关注新话题（仅分类）
+)
+
+// String 返回通知级别的中文名称
+func (l NotificationLevel) String() string {
+	switch l {
+	case NotificationMuted:
+		return "静音"
+	case NotificationRegular:
+		return "普通"
+	case NotificationTracking:
+		return "跟踪"
+	case NotificationWatching:
+		return "关注"
+	case NotificationWatchingFirstPost:
+		return "关注新话题"
+	default:
+		return fmt.Sprintf("未知(%d)", int(l))
+	}
+}
+
+// ParseNotificationLevel 解析通知级别名称（muted/normal/tracking/watching/first）
+func ParseNotificationLevel(name string) (NotificationLevel, error) {
+	switch strings.ToLower(name) {
+	case "muted", "mute", "0":
+		return NotificationMuted, nil
+	case "normal", "regular", "1":
+		return NotificationRegular, nil
+	case "tracking", "track", "2":
+		return NotificationTracking, nil
+	case "watching", "watch", "3":
+		return NotificationWatching, nil
+	case "watching_first_post", "first", "4":
+		return NotificationWatchingFirstPost, nil
+	}
+	return 0, fmt.Errorf("未知的通知级别: %s", name)
+}
+
+// SetTopicNotificationLevel 设置话题的通知级别
+func (c *Client) SetTopicNotificationLevel(topicID int, level NotificationLevel) error {
+	if level == NotificationWatchingFirstPost {
+		return fmt.Errorf("话题不支持 %s 级别", level)
+	}
+	return c.setNotificationLevel(fmt.Sprintf("/t/%d/notifications", topicID), level)
+}
+
+// SetCategoryNotificationLevel 设置分类的通知级别
+func (c *Client) SetCategoryNotificationLevel(categoryID int, level NotificationLevel) error {
+	return c.setNotificationLevel(fmt.Sprintf("/category/%d/notifications", categoryID), level)
+}
+
+func (c *Client) setNotificationLevel(path string, level NotificationLevel) error {
+	payload := map[string]any{
+		"notification_level": int(level),
+	}
+
+	jsonData, _ := json.Marshal(payload)
+	req, _ := http.NewRequest(http.MethodPost, c.baseURL+path, strings.NewReader(string(jsonData)))
+	req.Header = c.headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Origin", c.baseURL)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("设置通知级别失败 (状态码 %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
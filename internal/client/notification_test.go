@@ -0,0 +1,90 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTopicNotificationLevelDecoding(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		unset bool
+		muted bool
+	}{
+		{"missing", `{"id":1,"title":"无跟踪数据"}`, true, false},
+		{"null", `{"id":1,"notification_level":null}`, true, false},
+		{"muted", `{"id":1,"notification_level":0}`, false, true},
+		{"regular", `{"id":1,"notification_level":1}`, false, false},
+		{"watching", `{"id":1,"notification_level":3}`, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var topic Topic
+			if err := json.Unmarshal([]byte(tt.json), &topic); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got := topic.NotificationLevel == nil; got != tt.unset {
+				t.Errorf("NotificationLevel unset = %v, want %v", got, tt.unset)
+			}
+			if got := topic.Muted(); got != tt.muted {
+				t.Errorf("Muted() = %v, want %v", got, tt.muted)
+			}
+		})
+	}
+}
+
+func TestTopicListWithoutNotificationLevel(t *testing.T) {
+	data := `{"topic_list":{"topics":[
+		{"id":1,"title":"未访问的话题"},
+		{"id":2,"title":"已静音的话题","notification_level":0},
+		{"id":3,"title":"跟踪的话题","notification_level":2}
+	]}}`
+	var result struct {
+		TopicList struct {
+			Topics []Topic `json:"topics"`
+		} `json:"topic_list"`
+	}
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	var muted []int
+	for _, topic := range result.TopicList.Topics {
+		if topic.Muted() {
+			muted = append(muted, topic.ID)
+		}
+	}
+	if len(muted) != 1 || muted[0] != 2 {
+		t.Errorf("muted topics = %v, want [2]", muted)
+	}
+}
+
+func TestTopicDetailNotificationLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want NotificationLevel
+	}{
+		{"missing details", `{"id":1}`, NotificationRegular},
+		{"missing level", `{"id":1,"details":{}}`, NotificationRegular},
+		{"muted", `{"id":1,"details":{"notification_level":0}}`, NotificationMuted},
+		{"watching", `{"id":1,"details":{"notification_level":3}}`, NotificationWatching},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var detail TopicDetail
+			if err := json.Unmarshal([]byte(tt.json), &detail); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if got := detail.NotificationLevel(); got != tt.want {
+				t.Errorf("NotificationLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var detail TopicDetail
+	detail.SetNotificationLevel(NotificationTracking)
+	if got := detail.NotificationLevel(); got != NotificationTracking {
+		t.Errorf("after SetNotificationLevel: %v, want %v", got, NotificationTracking)
+	}
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// TopPeriods 排行榜（top）支持的时间范围
+var TopPeriods = []string{"daily", "weekly", "monthly", "quarterly", "yearly", "all"}
+
+// TopicOrders 话题列表支持的排序字段：最新活动、浏览数、点赞数、帖子数、创建时间
+var TopicOrders = []string{"activity", "views", "likes", "posts", "created"}
+
+// TopicOrder 话题列表的排序方式，By 为空时使用论坛默认顺序
+type TopicOrder struct {
+	By        string
+	Ascending bool
+}
+
+// ValidTopPeriod 是否为支持的排行榜时间范围
+func ValidTopPeriod(period string) bool {
+	for _, p := range TopPeriods {
+		if p == period {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTopicOrder 解析排序字段和方向（asc、desc，默认 desc），字段为 default 或空时使用论坛默认顺序
+func ParseTopicOrder(by, direction string) (TopicOrder, error) {
+	if by == "" || by == "default" {
+		return TopicOrder{}, nil
+	}
+	valid := false
+	for _, o := range TopicOrders {
+		if o == by {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return TopicOrder{}, fmt.Errorf("不支持的排序字段: %s（可选 %s）", by, strings.Join(TopicOrders, ", "))
+	}
+	switch direction {
+	case "", "desc":
+		return TopicOrder{By: by}, nil
+	case "asc":
+		return TopicOrder{By: by, Ascending: true}, nil
+	}
+	return TopicOrder{}, fmt.Errorf("不支持的排序方向: %s（可选 asc, desc）", direction)
+}
+
+// String 返回 "views desc" 形式的描述，默认顺序返回 "default"
+func (o TopicOrder) String() string {
+	if o.By == "" {
+		return "default"
+	}
+	if o.Ascending {
+		return o.By + " asc"
+	}
+	return o.By + " desc"
+}
+
+// query 返回话题列表接口的排序参数，包括开头的 ?
+func (o TopicOrder) query() string {
+	if o.By == "" {
+		return ""
+	}
+	v := url.Values{}
+	v.Set("order", o.By)
+	if o.Ascending {
+		v.Set("ascending", "true")
+	}
+	return "?" + v.Encode()
+}
+
+// SortTopics 在本地按排序方式稳定排序已加载的话题，用于服务器不支持排序参数的列表（hot、new、top）
+func SortTopics(topics []Topic, order TopicOrder) {
+	var less func(a, b Topic) bool
+	switch order.By {
+	case "activity":
+		less = func(a, b Topic) bool { return a.BumpedAt < b.BumpedAt }
+	case "views":
+		less = func(a, b Topic) bool { return a.Views < b.Views }
+	case "likes":
+		less = func(a, b Topic) bool { return a.LikeCount < b.LikeCount }
+	case "posts":
+		less = func(a, b Topic) bool { return a.PostsCount < b.PostsCount }
+	case "created":
+		less = func(a, b Topic) bool { return a.CreatedAt < b.CreatedAt }
+	default:
+		return
+	}
+	sort.SliceStable(topics, func(i, j int) bool {
+		if order.Ascending {
+			return less(topics[i], topics[j])
+		}
+		return less(topics[j], topics[i])
+	})
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTopicOrder(t *testing.T) {
+	tests := []struct {
+		by, direction string
+		want          TopicOrder
+		err           string
+	}{
+		{"", "", TopicOrder{}, ""},
+		{"default", "asc", TopicOrder{}, ""},
+		{"views", "", TopicOrder{By: "views"}, ""},
+		{"views", "desc", TopicOrder{By: "views"}, ""},
+		{"likes", "asc", TopicOrder{By: "likes", Ascending: true}, ""},
+		{"created", "asc", TopicOrder{By: "created", Ascending: true}, ""},
+		{"replies", "", TopicOrder{}, "不支持的排序字段: replies"},
+		{"Views", "", TopicOrder{}, "不支持的排序字段"},
+		{"posts", "up", TopicOrder{}, "不支持的排序方向: up"},
+		{"posts", "ASC", TopicOrder{}, "不支持的排序方向"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.by+" "+tt.direction, func(t *testing.T) {
+			got, err := ParseTopicOrder(tt.by, tt.direction)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTopicOrder: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTopicOrder = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortTopics(t *testing.T) {
+	// ID 5 与 ID 1 各字段都相同，用于检查稳定排序
+	topics := []Topic{
+		{ID: 1, Views: 20, LikeCount: 3, PostsCount: 7, BumpedAt: "2024-01-02T00:00:00Z", CreatedAt: "2023-12-01T00:00:00Z"},
+		{ID: 2, Views: 10, LikeCount: 9, PostsCount: 2, BumpedAt: "2024-01-05T00:00:00Z", CreatedAt: "2023-11-01T00:00:00Z"},
+		{ID: 3, Views: 30, LikeCount: 1, PostsCount: 5, BumpedAt: "2024-01-01T00:00:00Z", CreatedAt: "2024-01-01T00:00:00Z"},
+		{ID: 4, Views: 15, LikeCount: 5, PostsCount: 1, BumpedAt: "2024-01-03T00:00:00Z", CreatedAt: "2023-10-01T00:00:00Z"},
+		{ID: 5, Views: 20, LikeCount: 3, PostsCount: 7, BumpedAt: "2024-01-02T00:00:00Z", CreatedAt: "2023-12-01T00:00:00Z"},
+	}
+	tests := []struct {
+		order TopicOrder
+		want  []int
+	}{
+		{TopicOrder{}, []int{1, 2, 3, 4, 5}},
+		{TopicOrder{By: "unknown"}, []int{1, 2, 3, 4, 5}},
+		{TopicOrder{By: "activity"}, []int{2, 4, 1, 5, 3}},
+		{TopicOrder{By: "activity", Ascending: true}, []int{3, 1, 5, 4, 2}},
+		{TopicOrder{By: "views"}, []int{3, 1, 5, 4, 2}},
+		{TopicOrder{By: "views", Ascending: true}, []int{2, 4, 1, 5, 3}},
+		{TopicOrder{By: "likes"}, []int{2, 4, 1, 5, 3}},
+		{TopicOrder{By: "likes", Ascending: true}, []int{3, 1, 5, 4, 2}},
+		{TopicOrder{By: "posts"}, []int{1, 5, 3, 2, 4}},
+		{TopicOrder{By: "posts", Ascending: true}, []int{4, 2, 3, 1, 5}},
+		{TopicOrder{By: "created"}, []int{3, 1, 5, 2, 4}},
+		{TopicOrder{By: "created", Ascending: true}, []int{4, 2, 1, 5, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.order.String(), func(t *testing.T) {
+			sorted := append([]Topic(nil), topics...)
+			SortTopics(sorted, tt.order)
+			var got []int
+			for _, topic := range sorted {
+				got = append(got, topic.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortTopics(%s) = %v, want %v", tt.order, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// Poll 帖子中的投票（discourse-poll 插件）
+type Poll struct {
+	Name    string       `json:"name"`
+	Type    string       `json:"type"`    // regular / multiple / number
+	Status  string       `json:"status"`  // open / closed
+	Results string       `json:"results"` // always / on_vote / on_close / staff_only
+	Public  bool         `json:"public"`
+	Title   string       `json:"title"`
+	Min     int          `json:"min"`
+	Max     int          `json:"max"`
+	Voters  int          `json:"voters"`
+	Close   string       `json:"close"` // 自动关闭时间
+	Options []PollOption `json:"options"`
+}
+
+type PollOption struct {
+	ID    string `json:"id"`
+	HTML  string `json:"html"`
+	Votes int    `json:"votes"`
+}
+
+// IsOpen 投票是否仍可参与
+func (p Poll) IsOpen() bool {
+	return p.Status != "closed"
+}
+
+// IsMultiple 是否为多选投票
+func (p Poll) IsMultiple() bool {
+	return p.Type == "multiple"
+}
+
+// CanSeeResults 根据投票设置判断当前用户是否可以查看结果
+func (p Poll) CanSeeResults(voted bool) bool {
+	switch p.Results {
+	case "on_vote":
+		return voted || !p.IsOpen()
+	case "on_close":
+		return !p.IsOpen()
+	case "staff_only":
+		return false
+	default:
+		return true
+	}
+}
+
+// TotalVotes 所有选项的票数之和
+func (p Poll) TotalVotes() int {
+	total := 0
+	for _, o := range p.Options {
+		total += o.Votes
+	}
+	return total
+}
+
+// VotePoll 在投票中选择选项，返回更新后的投票和当前用户的选择
+func (c *Client) VotePoll(postID int, pollName string, optionIDs []string) (*Poll, []string, error) {
+	payload := map[string]any{
+		"post_id":   postID,
+		"poll_name": pollName,
+		"options":   optionIDs,
+	}
+	defer c.cache.forgetPost(postID)
+	return c.doPollVote(http.MethodPut, payload)
+}
+
+// RemovePollVote 撤回在投票中的选择
+func (c *Client) RemovePollVote(postID int, pollName string) (*Poll, error) {
+	payload := map[string]any{
+		"post_id":   postID,
+		"poll_name": pollName,
+	}
+	poll, _, err := c.doPollVote(http.MethodDelete, payload)
+	c.cache.forgetPost(postID)
+	return poll, err
+}
+
+func (c *Client) doPollVote(method string, payload map[string]any) (*Poll, []string, error) {
+	jsonData, _ := json.Marshal(payload)
+	req, _ := http.NewRequest(method, c.baseURL+"/polls/vote", strings.NewReader(string(jsonData)))
+	req.Header = c.headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Origin", c.baseURL)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, nil, fmt.Errorf("投票失败 (状态码 %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result struct {
+		Poll Poll     `json:"poll"`
+		Vote []string `json:"vote"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, nil, err
+	}
+
+	return &result.Poll, result.Vote, nil
+}
+
+var pollStartRe = regexp.MustCompile(`<div class="poll[" ][^>]*>`)
+var divTagRe = regexp.MustCompile(`(?i)<(/?)div\b[^>]*>`)
+
+// StripPolls 去除 cooked 中的投票 HTML，投票内容由 Post.Polls 单独渲染
+func StripPolls(cooked string) string {
+	var b strings.Builder
+	for {
+		loc := pollStartRe.FindStringIndex(cooked)
+		if loc == nil {
+			b.WriteString(cooked)
+			break
+		}
+		b.WriteString(cooked[:loc[0]])
+
+		// 找到与投票开始标签配对的 </div>
+		rest := cooked[loc[1]:]
+		depth := 1
+		end := len(rest)
+		for _, m := range divTagRe.FindAllStringSubmatchIndex(rest, -1) {
+			if m[3] > m[2] {
+				depth--
+			} else {
+				depth++
+			}
+			if depth == 0 {
+				end = m[1]
+				break
+			}
+		}
+
+		cooked = rest[end:]
+	}
+	return b.String()
+}
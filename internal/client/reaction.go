@@ -0,0 +1,124 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// Reaction 帖子上某个表情回应的统计（discourse-reactions 插件）
+type Reaction struct {
+	ID    string `json:"id"` // 表情短码，例如 heart、+1
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// CurrentUserReaction 当前用户在帖子上的表情回应
+type CurrentUserReaction struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	CanUndo bool   `json:"can_undo"`
+}
+
+// DefaultReactions 无法获取站点配置时使用的默认表情列表
+var DefaultReactions = []string{"heart", "+1", "laughing", "open_mouth", "clap", "confetti_ball", "hugs"}
+
+var preloadedRe = regexp.MustCompile(`id="data-preloaded"[^>]*data-preloaded="([^"]*)"`)
+
+// GetAvailableReactions 获取站点启用的表情回应列表
+func (c *Client) GetAvailableReactions() ([]string, error) {
+	if len(c.reactions) > 0 {
+		return c.reactions, nil
+	}
+
+	// 站点设置只在首页的预加载数据中提供
+	req, _ := http.NewRequest(http.MethodGet, c.baseURL+"/", nil)
+	req.Header = c.headers.Clone()
+
+	resp, err := c.do(req)
+	if err != nil {
+		return DefaultReactions, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return DefaultReactions, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	match := preloadedRe.FindSubmatch(bodyBytes)
+	if match == nil {
+		return DefaultReactions, fmt.Errorf("未找到站点预加载数据")
+	}
+
+	var preloaded map[string]string
+	if err := json.Unmarshal([]byte(html.UnescapeString(string(match[1]))), &preloaded); err != nil {
+		return DefaultReactions, err
+	}
+
+	var settings struct {
+		EnabledReactions string `json:"discourse_reactions_enabled_reactions"`
+		ReactionForLike  string `json:"discourse_reactions_reaction_for_like"`
+	}
+	if err := json.Unmarshal([]byte(preloaded["siteSettings"]), &settings); err != nil {
+		return DefaultReactions, err
+	}
+
+	var reactions []string
+	if settings.ReactionForLike != "" {
+		reactions = append(reactions, settings.ReactionForLike)
+	}
+	for _, r := range strings.Split(settings.EnabledReactions, "|") {
+		if r != "" && r != settings.ReactionForLike {
+			reactions = append(reactions, r)
+		}
+	}
+	if len(reactions) == 0 {
+		return DefaultReactions, nil
+	}
+
+	c.reactions = reactions
+	return reactions, nil
+}
+
+// ToggleReaction 切换当前用户在帖子上的表情回应，返回更新后的帖子
+func (c *Client) ToggleReaction(postID int, reaction string) (*Post, error) {
+	toggleURL := fmt.Sprintf("%s/discourse-reactions/posts/%d/custom-reactions/%s/toggle.json",
+		c.baseURL, postID, url.PathEscape(reaction))
+	defer c.cache.forgetPost(postID)
+
+	req, _ := http.NewRequest(http.MethodPut, toggleURL, nil)
+	req.Header = c.headers.Clone()
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Origin", c.baseURL)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("表情回应失败 (状态码 %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var post Post
+	if err := json.Unmarshal(bodyBytes, &post); err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
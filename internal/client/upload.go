@@ -0,0 +1,105 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// Upload 上传文件的结果
+type Upload struct {
+	ID               int    `json:"id"`
+	URL              string `json:"url"`
+	ShortURL         string `json:"short_url"` // upload://xxx.png
+	OriginalFilename string `json:"original_filename"`
+	Extension        string `json:"extension"`
+	Width            int    `json:"width"`
+	Height           int    `json:"height"`
+	Filesize         int    `json:"filesize"`
+	HumanFilesize    string `json:"human_filesize"`
+}
+
+// IsImage 是否为图片（Discourse 只为图片返回尺寸）
+func (u Upload) IsImage() bool {
+	return u.Width > 0 && u.Height > 0
+}
+
+// Markdown 返回可直接插入帖子的 Markdown
+func (u Upload) Markdown() string {
+	name := strings.TrimSuffix(u.OriginalFilename, filepath.Ext(u.OriginalFilename))
+	if u.IsImage() {
+		return fmt.Sprintf("![%s|%dx%d](%s)", name, u.Width, u.Height, u.ShortURL)
+	}
+	return fmt.Sprintf("[%s|attachment](%s) (%s)", u.OriginalFilename, u.ShortURL, u.HumanFilesize)
+}
+
+// ExpandPath 展开路径开头的 ~
+func ExpandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, path[1:])
+	}
+	return path
+}
+
+// UploadFile 上传本地文件，用于在帖子中插入图片或附件
+func (c *Client) UploadFile(path string) (*Upload, error) {
+	path = ExpandPath(path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("type", "composer")
+	writer.WriteField("synchronous", "true")
+
+	part, err := writer.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, err
+	}
+	writer.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, c.baseURL+"/uploads.json", &body)
+	req.Header = c.headers.Clone()
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Origin", c.baseURL)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("上传失败 (状态码 %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var upload Upload
+	if err := json.Unmarshal(bodyBytes, &upload); err != nil {
+		return nil, err
+	}
+
+	return &upload, nil
+}
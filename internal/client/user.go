@@ -0,0 +1,111 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	http "github.com/bogdanfinn/fhttp"
+)
+
+// UserProfile 用户资料（/u/{username}.json）
+type UserProfile struct {
+	ID               int    `json:"id"`
+	Username         string `json:"username"`
+	Name             string `json:"name"`
+	Title            string `json:"title"`
+	AvatarTemplate   string `json:"avatar_template"`
+	TrustLevel       int    `json:"trust_level"`
+	Moderator        bool   `json:"moderator"`
+	Admin            bool   `json:"admin"`
+	CreatedAt        string `json:"created_at"`
+	LastSeenAt       string `json:"last_seen_at"`
+	LastPostedAt     string `json:"last_posted_at"`
+	BioExcerpt       string `json:"bio_excerpt"`
+	BioCooked        string `json:"bio_cooked"`
+	Location         string `json:"location"`
+	Website          string `json:"website"`
+	BadgeCount       int    `json:"badge_count"`
+	ProfileViewCount int    `json:"profile_view_count"`
+	TimeRead         int    `json:"time_read"` // 秒
+}
+
+// UserSummary 用户统计摘要（/u/{username}/summary.json）
+type UserSummary struct {
+	LikesGiven     int `json:"likes_given"`
+	LikesReceived  int `json:"likes_received"`
+	TopicsEntered  int `json:"topics_entered"`
+	PostsReadCount int `json:"posts_read_count"`
+	DaysVisited    int `json:"days_visited"`
+	TopicCount     int `json:"topic_count"`
+	PostCount      int `json:"post_count"`
+	TimeRead       int `json:"time_read"` // 秒
+	SolvedCount    int `json:"solved_count"`
+}
+
+// GetUserProfile 获取用户资料
+func (c *Client) GetUserProfile(username string) (*UserProfile, error) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/u/%s.json", c.baseURL, url.PathEscape(username)), nil)
+	req.Header = c.headers.Clone()
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("用户不存在: %s", username)
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	var result struct {
+		User UserProfile `json:"user"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.User, nil
+}
+
+// GetUserSummary 获取用户统计摘要
+func (c *Client) GetUserSummary(username string) (*UserSummary, error) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/u/%s/summary.json", c.baseURL, url.PathEscape(username)), nil)
+	req.Header = c.headers.Clone()
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-CSRF-Token", c.csrfToken)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 403 {
+		return nil, fmt.Errorf("被 Cloudflare 拦截 (403)")
+	}
+
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("用户不存在: %s", username)
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	var result struct {
+		UserSummary UserSummary `json:"user_summary"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, err
+	}
+
+	return &result.UserSummary, nil
+}
@@ -0,0 +1,145 @@
+// Package config 读取 ~/.linuxdo_config.json 中的用户配置
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config 用户配置，缺省字段使用零值
+type Config struct {
+	MCP MCPConfig `json:"mcp"`
+	UI  UIConfig  `json:"ui"`
+}
+
+// UIConfig TUI 和 CLI 的显示配置
+type UIConfig struct {
+	// SyntaxHighlight 是否对帖子中的代码块做语法高亮（默认开启）
+	SyntaxHighlight *bool `json:"syntax_highlight"`
+
+	// CodeStyle 语法高亮配色方案，可选值见 https://xyproto.github.io/splash/docs/
+	CodeStyle string `json:"code_style"`
+
+	// InlineImages 是否在 TUI 中直接显示帖子图片的预览（默认关闭，可按 i 切换）
+	InlineImages bool `json:"inline_images"`
+
+	// ImageProtocol 全屏查看图片时使用的协议：auto、kitty、iterm、sixel、halfblocks
+	ImageProtocol string `json:"image_protocol"`
+
+	// Keymap TUI 快捷键预设方案：default、vim、emacs
+	Keymap string `json:"keymap"`
+
+	// Keys 自定义快捷键，操作名 -> 按键列表，会覆盖预设方案中的同名操作；空列表表示禁用
+	Keys map[string][]string `json:"keys"`
+
+	// Theme TUI 配色主题：auto（默认，根据终端背景和 NO_COLOR 自动选择）、dark、light、mono 或 Themes 中的自定义主题
+	Theme string `json:"theme"`
+
+	// Themes 自定义主题，主题名 -> 配色
+	Themes map[string]ThemeConfig `json:"themes"`
+
+	// Layout 话题列表布局：auto（默认，终端足够宽时左右分栏）、split（总是分栏）、single（只显示列表）
+	Layout string `json:"layout"`
+
+	// PreviewReplies 分栏预览中除首帖外显示的最新回复数（默认 0）
+	PreviewReplies int `json:"preview_replies"`
+}
+
+// ThemeConfig 自定义主题的配色，颜色为 #RRGGBB 或 ANSI 色号，未设置的颜色沿用 Base 主题
+type ThemeConfig struct {
+	Base       string `json:"base"` // dark（默认）、light 或 mono
+	Primary    string `json:"primary"`
+	OnPrimary  string `json:"on_primary"`
+	Selected   string `json:"selected"`
+	OnSelected string `json:"on_selected"`
+	Muted      string `json:"muted"`
+	Warning    string `json:"warning"`
+	Highlight  string `json:"highlight"`
+	Mark       string `json:"mark"`
+	OnMark     string `json:"on_mark"`
+	Link       string `json:"link"`
+	Mention    string `json:"mention"`
+	Code       string `json:"code"`
+	CodeBg     string `json:"code_bg"`
+	Spoiler    string `json:"spoiler"`
+	CodeStyle  string `json:"code_style"`
+}
+
+// HighlightEnabled 是否开启语法高亮，未配置时默认开启
+func (c *UIConfig) HighlightEnabled() bool {
+	return c.SyntaxHighlight == nil || *c.SyntaxHighlight
+}
+
+// MCPConfig MCP Server 相关配置
+type MCPConfig struct {
+	// EnableFlag 是否向 AI 助手开放举报帖子的工具（默认关闭）
+	EnableFlag bool `json:"enable_flag"`
+
+	// UploadDirs 允许 upload_file 工具读取的目录，为空时禁止上传
+	UploadDirs []string `json:"upload_dirs"`
+}
+
+// AllowedUploadPath 检查文件是否位于允许上传的目录中，返回解析后的绝对路径
+func (c *MCPConfig) AllowedUploadPath(path string) (string, error) {
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	for _, dir := range c.UploadDirs {
+		allowed, err := resolvePath(dir)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(allowed, resolved)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+
+	return "", fmt.Errorf("文件 %s 不在允许上传的目录中（mcp.upload_dirs）", path)
+}
+
+// resolvePath 展开 ~ 并解析为不含符号链接的绝对路径
+func resolvePath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		path = filepath.Join(homeDir, path[1:])
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
+// Path 返回配置文件路径，可通过 LINUXDO_CONFIG 环境变量覆盖
+func Path() string {
+	if p := os.Getenv("LINUXDO_CONFIG"); p != "" {
+		return p
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".linuxdo_config.json")
+}
+
+// Load 读取配置文件，文件不存在时返回默认配置
+func Load() (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return &Config{}, fmt.Errorf("解析配置文件 %s 失败: %w", Path(), err)
+	}
+
+	return cfg, nil
+}
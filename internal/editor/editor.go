@@ -0,0 +1,74 @@
+// Package editor 调用外部编辑器（$VISUAL / $EDITOR）编辑回复内容
+package editor
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Name 返回要使用的编辑器命令，优先 $VISUAL，其次 $EDITOR
+func Name() string {
+	if e := os.Getenv("VISUAL"); e != "" {
+		return e
+	}
+	if e := os.Getenv("EDITOR"); e != "" {
+		return e
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// TempFile 创建预填内容的临时 Markdown 文件，返回文件路径
+func TempFile(initial string) (string, error) {
+	f, err := os.CreateTemp("", "ldo-reply-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(initial); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// Command 返回编辑指定文件的命令，编辑器可以带参数（例如 "code --wait"）
+func Command(path string) *exec.Cmd {
+	parts := strings.Fields(Name())
+	args := append(parts[1:], path)
+	return exec.Command(parts[0], args...)
+}
+
+// ReadFile 读取编辑结果并删除临时文件
+func ReadFile(path string) (string, error) {
+	defer os.Remove(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Edit 在当前终端中打开编辑器并等待退出，返回编辑后的内容
+func Edit(initial string) (string, error) {
+	path, err := TempFile(initial)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := Command(path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return ReadFile(path)
+}
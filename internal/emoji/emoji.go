@@ -0,0 +1,365 @@
+// Package emoji 将 Discourse 的 :shortcode: 表情转换为 Unicode 字符
+package emoji
+
+import (
+	"regexp"
+	"strings"
+)
+
+var shortcodeRe = regexp.MustCompile(`:([a-z0-9_+\-]+)(?::t([1-6]))?:`)
+
+// 肤色修饰符 :t1: ~ :t6:
+var skinTones = []string{"", "", "\U0001F3FB", "\U0001F3FC", "\U0001F3FD", "\U0001F3FE", "\U0001F3FF"}
+
+// codes Discourse（twitter 表情集）常用短码到 Unicode 的映射
+var codes = map[string]string{
+	// 表情
+	"grinning":                       "😀",
+	"smiley":                         "😃",
+	"smile":                          "😄",
+	"grin":                           "😁",
+	"laughing":                       "😆",
+	"sweat_smile":                    "😅",
+	"rofl":                           "🤣",
+	"joy":                            "😂",
+	"slightly_smiling_face":          "🙂",
+	"upside_down_face":               "🙃",
+	"wink":                           "😉",
+	"blush":                          "😊",
+	"innocent":                       "😇",
+	"smiling_face_with_three_hearts": "🥰",
+	"heart_eyes":                     "😍",
+	"star_struck":                    "🤩",
+	"kissing_heart":                  "😘",
+	"yum":                            "😋",
+	"stuck_out_tongue":               "😛",
+	"stuck_out_tongue_winking_eye":   "😜",
+	"zany_face":                      "🤪",
+	"money_mouth_face":               "🤑",
+	"hugs":                           "🤗",
+	"hugging_face":                   "🤗",
+	"face_with_hand_over_mouth":      "🤭",
+	"shushing_face":                  "🤫",
+	"thinking":                       "🤔",
+	"thinking_face":                  "🤔",
+	"zipper_mouth_face":              "🤐",
+	"raised_eyebrow":                 "🤨",
+	"neutral_face":                   "😐",
+	"expressionless":                 "😑",
+	"no_mouth":                       "😶",
+	"smirk":                          "😏",
+	"unamused":                       "😒",
+	"roll_eyes":                      "🙄",
+	"face_with_rolling_eyes":         "🙄",
+	"grimacing":                      "😬",
+	"lying_face":                     "🤥",
+	"relieved":                       "😌",
+	"pensive":                        "😔",
+	"sleepy":                         "😪",
+	"drooling_face":                  "🤤",
+	"sleeping":                       "😴",
+	"mask":                           "😷",
+	"face_with_thermometer":          "🤒",
+	"nauseated_face":                 "🤢",
+	"vomiting_face":                  "🤮",
+	"sneezing_face":                  "🤧",
+	"hot_face":                       "🥵",
+	"cold_face":                      "🥶",
+	"woozy_face":                     "🥴",
+	"dizzy_face":                     "😵",
+	"exploding_head":                 "🤯",
+	"cowboy_hat_face":                "🤠",
+	"partying_face":                  "🥳",
+	"sunglasses":                     "😎",
+	"nerd_face":                      "🤓",
+	"confused":                       "😕",
+	"worried":                        "😟",
+	"slightly_frowning_face":         "🙁",
+	"open_mouth":                     "😮",
+	"hushed":                         "😯",
+	"astonished":                     "😲",
+	"flushed":                        "😳",
+	"pleading_face":                  "🥺",
+	"frowning":                       "😦",
+	"anguished":                      "😧",
+	"fearful":                        "😨",
+	"cold_sweat":                     "😰",
+	"disappointed_relieved":          "😥",
+	"cry":                            "😢",
+	"sob":                            "😭",
+	"scream":                         "😱",
+	"confounded":                     "😖",
+	"persevere":                      "😣",
+	"disappointed":                   "😞",
+	"sweat":                          "😓",
+	"weary":                          "😩",
+	"tired_face":                     "😫",
+	"yawning_face":                   "🥱",
+	"triumph":                        "😤",
+	"rage":                           "😡",
+	"angry":                          "😠",
+	"cursing_face":                   "🤬",
+	"smiling_imp":                    "😈",
+	"imp":                            "👿",
+	"skull":                          "💀",
+	"poop":                           "💩",
+	"hankey":                         "💩",
+	"clown_face":                     "🤡",
+	"ghost":                          "👻",
+	"alien":                          "👽",
+	"robot":                          "🤖",
+	"smiley_cat":                     "😺",
+	"see_no_evil":                    "🙈",
+	"hear_no_evil":                   "🙉",
+	"speak_no_evil":                  "🙊",
+	"melting_face":                   "🫠",
+	"saluting_face":                  "🫡",
+	"face_holding_back_tears":        "🥹",
+	"distorted_face":                 "😵‍💫",
+
+	// 手势
+	"+1":                 "👍",
+	"thumbsup":           "👍",
+	"-1":                 "👎",
+	"thumbsdown":         "👎",
+	"ok_hand":            "👌",
+	"pinching_hand":      "🤏",
+	"v":                  "✌️",
+	"crossed_fingers":    "🤞",
+	"love_you_gesture":   "🤟",
+	"metal":              "🤘",
+	"call_me_hand":       "🤙",
+	"point_left":         "👈",
+	"point_right":        "👉",
+	"point_up":           "☝️",
+	"point_up_2":         "👆",
+	"point_down":         "👇",
+	"raised_hand":        "✋",
+	"wave":               "👋",
+	"clap":               "👏",
+	"raised_hands":       "🙌",
+	"open_hands":         "👐",
+	"handshake":          "🤝",
+	"pray":               "🙏",
+	"muscle":             "💪",
+	"writing_hand":       "✍️",
+	"facepunch":          "👊",
+	"fist":               "✊",
+	"eyes":               "👀",
+	"eye":                "👁️",
+	"brain":              "🧠",
+	"person_facepalming": "🤦",
+	"facepalm":           "🤦",
+	"person_shrugging":   "🤷",
+	"shrug":              "🤷",
+	"man_shrugging":      "🤷‍♂️",
+	"woman_shrugging":    "🤷‍♀️",
+
+	// 心形与符号
+	"heart":                       "❤️",
+	"orange_heart":                "🧡",
+	"yellow_heart":                "💛",
+	"green_heart":                 "💚",
+	"blue_heart":                  "💙",
+	"purple_heart":                "💜",
+	"black_heart":                 "🖤",
+	"white_heart":                 "🤍",
+	"broken_heart":                "💔",
+	"two_hearts":                  "💕",
+	"sparkling_heart":             "💖",
+	"heartpulse":                  "💗",
+	"heartbeat":                   "💓",
+	"revolving_hearts":            "💞",
+	"cupid":                       "💘",
+	"100":                         "💯",
+	"anger":                       "💢",
+	"boom":                        "💥",
+	"collision":                   "💥",
+	"dizzy":                       "💫",
+	"sweat_drops":                 "💦",
+	"dash":                        "💨",
+	"zzz":                         "💤",
+	"speech_balloon":              "💬",
+	"thought_balloon":             "💭",
+	"fire":                        "🔥",
+	"sparkles":                    "✨",
+	"star":                        "⭐",
+	"star2":                       "🌟",
+	"zap":                         "⚡",
+	"tada":                        "🎉",
+	"confetti_ball":               "🎊",
+	"gift":                        "🎁",
+	"trophy":                      "🏆",
+	"medal_sports":                "🏅",
+	"1st_place_medal":             "🥇",
+	"2nd_place_medal":             "🥈",
+	"3rd_place_medal":             "🥉",
+	"white_check_mark":            "✅",
+	"heavy_check_mark":            "✔️",
+	"ballot_box_with_check":       "☑️",
+	"x":                           "❌",
+	"negative_squared_cross_mark": "❎",
+	"warning":                     "⚠️",
+	"no_entry":                    "⛔",
+	"no_entry_sign":               "🚫",
+	"question":                    "❓",
+	"grey_question":               "❔",
+	"exclamation":                 "❗",
+	"grey_exclamation":            "❕",
+	"bangbang":                    "‼️",
+	"interrobang":                 "⁉️",
+	"information_source":          "ℹ️",
+	"arrow_up":                    "⬆️",
+	"arrow_down":                  "⬇️",
+	"arrow_left":                  "⬅️",
+	"arrow_right":                 "➡️",
+	"arrows_counterclockwise":     "🔄",
+	"new":                         "🆕",
+	"free":                        "🆓",
+	"up":                          "🆙",
+	"cool":                        "🆒",
+	"ok":                          "🆗",
+	"sos":                         "🆘",
+	"red_circle":                  "🔴",
+	"green_circle":                "🟢",
+	"large_blue_circle":           "🔵",
+	"yellow_circle":               "🟡",
+
+	// 物品与其他
+	"coffee":                     "☕",
+	"tea":                        "🍵",
+	"beer":                       "🍺",
+	"beers":                      "🍻",
+	"pizza":                      "🍕",
+	"hamburger":                  "🍔",
+	"watermelon":                 "🍉",
+	"melon":                      "🍈",
+	"apple":                      "🍎",
+	"cake":                       "🍰",
+	"birthday":                   "🎂",
+	"popcorn":                    "🍿",
+	"rocket":                     "🚀",
+	"airplane":                   "✈️",
+	"car":                        "🚗",
+	"computer":                   "💻",
+	"desktop_computer":           "🖥️",
+	"keyboard":                   "⌨️",
+	"iphone":                     "📱",
+	"bulb":                       "💡",
+	"wrench":                     "🔧",
+	"hammer":                     "🔨",
+	"hammer_and_wrench":          "🛠️",
+	"gear":                       "⚙️",
+	"lock":                       "🔒",
+	"unlock":                     "🔓",
+	"key":                        "🔑",
+	"link":                       "🔗",
+	"paperclip":                  "📎",
+	"pushpin":                    "📌",
+	"memo":                       "📝",
+	"pencil":                     "📝",
+	"pencil2":                    "✏️",
+	"book":                       "📖",
+	"books":                      "📚",
+	"bookmark":                   "🔖",
+	"mag":                        "🔍",
+	"mag_right":                  "🔎",
+	"bell":                       "🔔",
+	"no_bell":                    "🔕",
+	"mega":                       "📣",
+	"loudspeaker":                "📢",
+	"moneybag":                   "💰",
+	"dollar":                     "💵",
+	"gem":                        "💎",
+	"package":                    "📦",
+	"email":                      "📧",
+	"envelope":                   "✉️",
+	"calendar":                   "📆",
+	"date":                       "📅",
+	"clock":                      "🕐",
+	"hourglass":                  "⌛",
+	"stopwatch":                  "⏱️",
+	"chart_with_upwards_trend":   "📈",
+	"chart_with_downwards_trend": "📉",
+	"bar_chart":                  "📊",
+	"clipboard":                  "📋",
+	"file_folder":                "📁",
+	"penguin":                    "🐧",
+	"dog":                        "🐶",
+	"cat":                        "🐱",
+	"fox_face":                   "🦊",
+	"panda_face":                 "🐼",
+	"pig":                        "🐷",
+	"monkey_face":                "🐵",
+	"bug":                        "🐛",
+	"snake":                      "🐍",
+	"crab":                       "🦀",
+	"whale":                      "🐳",
+	"sunny":                      "☀️",
+	"cloud":                      "☁️",
+	"umbrella":                   "☔",
+	"snowflake":                  "❄️",
+	"rainbow":                    "🌈",
+	"earth_asia":                 "🌏",
+	"globe_with_meridians":       "🌐",
+	"rose":                       "🌹",
+	"four_leaf_clover":           "🍀",
+	"seedling":                   "🌱",
+	"cn":                         "🇨🇳",
+	"us":                         "🇺🇸",
+	"jp":                         "🇯🇵",
+	"checkered_flag":             "🏁",
+	"triangular_flag_on_post":    "🚩",
+	"video_game":                 "🎮",
+	"dart":                       "🎯",
+	"game_die":                   "🎲",
+	"musical_note":               "🎵",
+	"notes":                      "🎶",
+	"art":                        "🎨",
+	"movie_camera":               "🎥",
+	"camera":                     "📷",
+	"tv":                         "📺",
+	"battery":                    "🔋",
+	"electric_plug":              "🔌",
+	"satellite":                  "📡",
+	"shield":                     "🛡️",
+	"crown":                      "👑",
+	"ring":                       "💍",
+	"lipstick":                   "💄",
+	"kiss":                       "💋",
+	"footprints":                 "👣",
+	"man_technologist":           "👨‍💻",
+	"woman_technologist":         "👩‍💻",
+}
+
+// Lookup 根据短码（不含冒号）查找对应的 Unicode 表情
+func Lookup(name string) (string, bool) {
+	e, ok := codes[strings.ToLower(name)]
+	return e, ok
+}
+
+// Replace 将文本中的 :shortcode: 替换为 Unicode 表情，未知短码保持原样
+func Replace(text string) string {
+	if !strings.Contains(text, ":") {
+		return text
+	}
+	return shortcodeRe.ReplaceAllStringFunc(text, func(match string) string {
+		sub := shortcodeRe.FindStringSubmatch(match)
+		e, ok := codes[sub[1]]
+		if !ok {
+			return match
+		}
+		if sub[2] != "" {
+			e += skinTones[sub[2][0]-'0']
+		}
+		return e
+	})
+}
+
+// Render 返回短码对应的表情，未知短码返回 :name:
+func Render(name string) string {
+	if e, ok := Lookup(name); ok {
+		return e
+	}
+	return ":" + name + ":"
+}
@@ -0,0 +1,208 @@
+// Package render 将 Discourse 帖子（cooked HTML）或 Markdown 解析为文档树，
+// 并渲染为终端样式文本、纯文本或 Markdown
+package render
+
+import "strings"
+
+// BlockKind 块级节点类型
+type BlockKind int
+
+const (
+	Paragraph BlockKind = iota
+	Heading
+	List
+	CodeBlock
+	Quote
+	Table
+	Rule
+	Onebox
+	Details
+)
+
+// Block 块级节点
+type Block struct {
+	Kind     BlockKind
+	Inlines  []Inline   // Paragraph、Heading 的内容
+	Level    int        // Heading 级别 1-6
+	Ordered  bool       // List 是否有序
+	Start    int        // 有序列表起始编号
+	Items    [][]Block  // List 的各项
+	Lang     string     // CodeBlock 语言
+	Text     string     // CodeBlock 代码；Onebox 摘要
+	Title    string     // Quote 被引用的用户；Onebox 标题；Details 摘要
+	URL      string     // Onebox 链接
+	Children []Block    // Quote、Details 的内容
+	Rows     [][]string // Table 各行，第一行为表头
+}
+
+// InlineKind 行内节点类型
+type InlineKind int
+
+const (
+	Text InlineKind = iota
+	Bold
+	Italic
+	Strike
+	Code
+	Link
+	Mention
+	Emoji
+	Image
+	Spoiler
+	LineBreak
+)
+
+// Inline 行内节点
+type Inline struct {
+	Kind     InlineKind
+	Text     string   // Text、Code、Emoji 的文本；Mention 的用户名；Image 的替代文本
+	URL      string   // Link、Image 的地址
+	Width    int      // Image 宽度
+	Height   int      // Image 高度
+	Children []Inline // Bold、Italic、Strike、Link、Spoiler 的内容
+}
+
+// Document 文档树
+type Document struct {
+	Blocks []Block
+}
+
+// LinkRef 文档中的一个链接
+type LinkRef struct {
+	Text string // 链接文字或预览卡片标题
+	URL  string
+}
+
+// walk 按出现顺序遍历所有块和行内节点
+func (d *Document) walk(block func(Block), inline func(Inline)) {
+	var visit func([]Inline)
+	visit = func(inlines []Inline) {
+		for _, in := range inlines {
+			inline(in)
+			visit(in.Children)
+		}
+	}
+	var walk func([]Block)
+	walk = func(blocks []Block) {
+		for _, b := range blocks {
+			block(b)
+			visit(b.Inlines)
+			for _, item := range b.Items {
+				walk(item)
+			}
+			walk(b.Children)
+		}
+	}
+	walk(d.Blocks)
+}
+
+// Images 返回文档中的所有图片（不含表情），按出现顺序
+func (d *Document) Images() []Inline {
+	var images []Inline
+	d.walk(func(Block) {}, func(in Inline) {
+		if in.Kind == Image {
+			images = append(images, in)
+		}
+	})
+	return images
+}
+
+// Links 返回文档中的所有链接（包括链接预览卡片），按出现顺序，重复的地址只保留第一个
+func (d *Document) Links() []LinkRef {
+	var links []LinkRef
+	seen := make(map[string]bool)
+	add := func(text, url string) {
+		if url == "" || seen[url] || strings.HasPrefix(url, "#") || strings.HasPrefix(url, "mailto:") {
+			return
+		}
+		seen[url] = true
+		if text = strings.TrimSpace(text); text == "" {
+			text = url
+		}
+		links = append(links, LinkRef{Text: text, URL: url})
+	}
+	d.walk(func(b Block) {
+		if b.Kind == Onebox {
+			add(b.Title, b.URL)
+		}
+	}, func(in Inline) {
+		if in.Kind == Link {
+			add(PlainText(in.Children), in.URL)
+		}
+	})
+	return links
+}
+
+// Text 返回文档中显示的文字，用于话题内搜索：块之间和强制换行处以换行分隔，
+// 不包括图片的占位文字和链接地址
+func (d *Document) Text() string {
+	var s strings.Builder
+	var inlines func([]Inline)
+	inlines = func(ins []Inline) {
+		for _, in := range ins {
+			switch in.Kind {
+			case Text, Code, Emoji:
+				s.WriteString(in.Text)
+			case Mention:
+				s.WriteString("@" + in.Text)
+			case LineBreak:
+				s.WriteString("\n")
+			case Image:
+				// 图片只显示占位文字，不参与搜索
+			case Link:
+				// 没有文字的链接显示地址
+				if strings.TrimSpace(PlainText(in.Children)) == "" {
+					s.WriteString(in.URL)
+				} else {
+					inlines(in.Children)
+				}
+			default:
+				inlines(in.Children)
+			}
+		}
+	}
+	var blocks func([]Block)
+	blocks = func(bs []Block) {
+		for _, b := range bs {
+			switch b.Kind {
+			case Paragraph, Heading:
+				inlines(b.Inlines)
+			case CodeBlock:
+				s.WriteString(b.Text)
+			case Onebox:
+				s.WriteString(b.Title + "\n" + b.Text)
+			case Details:
+				s.WriteString(b.Title)
+			case Table:
+				for _, row := range b.Rows {
+					s.WriteString(strings.Join(row, "\n") + "\n")
+				}
+			}
+			s.WriteString("\n")
+			for _, item := range b.Items {
+				blocks(item)
+			}
+			blocks(b.Children)
+		}
+	}
+	blocks(d.Blocks)
+	return s.String()
+}
+
+// PlainText 返回行内节点的纯文本内容
+func PlainText(inlines []Inline) string {
+	var s strings.Builder
+	for _, in := range inlines {
+		switch in.Kind {
+		case Text, Code, Emoji, Image:
+			s.WriteString(in.Text)
+		case Mention:
+			s.WriteString("@" + in.Text)
+		case LineBreak:
+			s.WriteString(" ")
+		default:
+			s.WriteString(PlainText(in.Children))
+		}
+	}
+	return s.String()
+}
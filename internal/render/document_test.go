@@ -0,0 +1,39 @@
+package render
+
+import "testing"
+
+func TestDocumentText(t *testing.T) {
+	tests := []struct {
+		name   string
+		cooked string
+		want   string
+	}{
+		{
+			name:   "段落和强制换行",
+			cooked: `<p>第一行<br>第二行</p><p><strong>加粗</strong>和<code>code</code></p>`,
+			want:   "第一行\n第二行\n加粗和code\n",
+		},
+		{
+			name:   "不含图片占位文字和链接地址",
+			cooked: `<p>看图 <img src="https://a.example/x.png" alt="截图" width="10" height="10"> 和 <a href="https://b.example">链接</a></p>`,
+			want:   "看图  和 链接\n",
+		},
+		{
+			name:   "没有文字的链接使用地址",
+			cooked: `<p><a href="https://b.example"></a></p>`,
+			want:   "https://b.example\n",
+		},
+		{
+			name:   "引用和代码块",
+			cooked: `<aside class="quote"><div class="title">alice:</div><blockquote><p>原话</p></blockquote></aside><pre><code>x := 1</code></pre>`,
+			want:   "\n原话\nx := 1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.cooked).Text(); got != tt.want {
+				t.Errorf("Text() = %q，应为 %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// DefaultCodeStyle 默认的语法高亮配色方案
+const DefaultCodeStyle = "monokai"
+
+// highlightCode 使用 chroma 对代码做词法分析，按行返回带样式的文本段
+// 语言无法识别时返回 nil，由调用方按普通代码块显示
+func highlightCode(code, lang, styleName string) [][]segment {
+	if lang == "" {
+		return nil
+	}
+	lexer := lexers.Get(lang)
+	if lexer == nil {
+		return nil
+	}
+	iter, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err != nil {
+		return nil
+	}
+
+	if styleName == "" {
+		styleName = DefaultCodeStyle
+	}
+	style := styles.Get(styleName)
+
+	lines := [][]segment{nil}
+	for _, tok := range iter.Tokens() {
+		st := tokenStyle(style.Get(tok.Type))
+		for i, part := range strings.Split(tok.Value, "\n") {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			if part != "" {
+				lines[len(lines)-1] = append(lines[len(lines)-1], segment{text: part, style: st})
+			}
+		}
+	}
+
+	// 词法分析器可能在末尾补一个换行
+	if n := len(lines); n > 1 && lines[n-1] == nil && !strings.HasSuffix(code, "\n") {
+		lines = lines[:n-1]
+	}
+	return lines
+}
+
+func tokenStyle(entry chroma.StyleEntry) lipgloss.Style {
+	st := lipgloss.NewStyle()
+	if entry.Colour.IsSet() {
+		st = st.Foreground(lipgloss.Color(entry.Colour.String()))
+	}
+	if entry.Bold == chroma.Yes {
+		st = st.Bold(true)
+	}
+	if entry.Italic == chroma.Yes {
+		st = st.Italic(true)
+	}
+	if entry.Underline == chroma.Yes {
+		st = st.Underline(true)
+	}
+	return st
+}
+
+// wrapCode 按显示宽度强制切分一行代码，保留缩进
+func wrapCode(segs []segment, width int) [][]segment {
+	if width <= 0 {
+		return [][]segment{segs}
+	}
+
+	lines := [][]segment{nil}
+	lineWidth := 0
+	for _, seg := range segs {
+		var cur strings.Builder
+		for _, r := range seg.text {
+			w := runewidth.RuneWidth(r)
+			if lineWidth+w > width && lineWidth > 0 {
+				if cur.Len() > 0 {
+					lines[len(lines)-1] = append(lines[len(lines)-1], segment{text: cur.String(), style: seg.style})
+					cur.Reset()
+				}
+				lines = append(lines, nil)
+				lineWidth = 0
+			}
+			cur.WriteRune(r)
+			lineWidth += w
+		}
+		if cur.Len() > 0 {
+			lines[len(lines)-1] = append(lines[len(lines)-1], segment{text: cur.String(), style: seg.style})
+		}
+	}
+	return lines
+}
@@ -0,0 +1,397 @@
+package render
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lhpqaq/ldo/internal/emoji"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var spaceRe = regexp.MustCompile(`\s+`)
+
+// Parse 将 Discourse 的 cooked HTML 解析为文档树
+// 投票（div.poll）会被跳过，由调用方单独渲染
+func Parse(cooked string) *Document {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(cooked), body)
+	if err != nil {
+		return &Document{Blocks: []Block{{Kind: Paragraph, Inlines: []Inline{{Kind: Text, Text: cooked}}}}}
+	}
+	for _, n := range nodes {
+		body.AppendChild(n)
+	}
+	return &Document{Blocks: parseBlocks(body)}
+}
+
+// parseBlocks 解析节点的子节点，连续的行内内容合并为段落
+func parseBlocks(n *html.Node) []Block {
+	var blocks []Block
+	var inlines []Inline
+
+	flush := func() {
+		if !isBlank(inlines) {
+			blocks = append(blocks, Block{Kind: Paragraph, Inlines: trimInlines(inlines)})
+		}
+		inlines = nil
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isBlockNode(c) {
+			flush()
+			blocks = append(blocks, parseBlock(c)...)
+		} else {
+			inlines = append(inlines, parseInline(c)...)
+		}
+	}
+	flush()
+	return blocks
+}
+
+func isBlockNode(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	switch n.DataAtom {
+	case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Ul, atom.Ol, atom.Pre, atom.Blockquote, atom.Aside, atom.Table, atom.Hr,
+		atom.Details, atom.Section, atom.Article, atom.Header, atom.Figure, atom.Iframe, atom.Video:
+		return true
+	}
+	return false
+}
+
+func parseBlock(n *html.Node) []Block {
+	switch n.DataAtom {
+	case atom.P, atom.Section, atom.Article, atom.Header, atom.Figure:
+		return parseBlocks(n)
+
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		return []Block{{Kind: Heading, Level: level, Inlines: trimInlines(parseInlines(n))}}
+
+	case atom.Ul, atom.Ol:
+		list := Block{Kind: List, Ordered: n.DataAtom == atom.Ol, Start: 1}
+		if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+			list.Start = start
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom == atom.Li {
+				list.Items = append(list.Items, parseBlocks(c))
+			}
+		}
+		return []Block{list}
+
+	case atom.Pre:
+		block := Block{Kind: CodeBlock}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom == atom.Code {
+				block.Lang = codeLang(c)
+			}
+		}
+		block.Text = strings.TrimRight(textContent(n), "\n")
+		return []Block{block}
+
+	case atom.Blockquote:
+		return []Block{{Kind: Quote, Children: parseBlocks(n)}}
+
+	case atom.Aside:
+		switch {
+		case hasClass(n, "quote"):
+			quote := Block{Kind: Quote, Title: attr(n, "data-username")}
+			if bq := findChild(n, atom.Blockquote); bq != nil {
+				quote.Children = parseBlocks(bq)
+			}
+			return []Block{quote}
+		case hasClass(n, "onebox"):
+			return []Block{parseOnebox(n)}
+		}
+		return parseBlocks(n)
+
+	case atom.Table:
+		return []Block{parseTable(n)}
+
+	case atom.Hr:
+		return []Block{{Kind: Rule}}
+
+	case atom.Details:
+		details := Block{Kind: Details}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom == atom.Summary {
+				details.Title = strings.TrimSpace(PlainText(parseInlines(c)))
+				n.RemoveChild(c)
+				break
+			}
+		}
+		details.Children = parseBlocks(n)
+		return []Block{details}
+
+	case atom.Iframe, atom.Video:
+		src := attr(n, "src")
+		if src == "" {
+			if source := findChild(n, atom.Source); source != nil {
+				src = attr(source, "src")
+			}
+		}
+		if src == "" {
+			return nil
+		}
+		return []Block{{Kind: Paragraph, Inlines: []Inline{{Kind: Link, URL: src}}}}
+
+	case atom.Div:
+		switch {
+		case hasClass(n, "poll"), hasClass(n, "meta"):
+			return nil
+		case hasClass(n, "spoiler"):
+			return []Block{{Kind: Paragraph, Inlines: []Inline{{Kind: Spoiler, Children: trimInlines(parseInlines(n))}}}}
+		}
+		return parseBlocks(n)
+	}
+	return parseBlocks(n)
+}
+
+// parseOnebox 解析链接预览卡片
+func parseOnebox(n *html.Node) Block {
+	box := Block{Kind: Onebox, URL: attr(n, "data-onebox-src")}
+
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.ElementNode {
+			switch c.DataAtom {
+			case atom.H3, atom.H4:
+				if box.Title == "" {
+					box.Title = strings.TrimSpace(textContent(c))
+					if a := findDescendant(c, atom.A); a != nil && box.URL == "" {
+						box.URL = attr(a, "href")
+					}
+				}
+				return
+			case atom.P:
+				if box.Text == "" {
+					box.Text = strings.TrimSpace(spaceRe.ReplaceAllString(textContent(c), " "))
+				}
+				return
+			case atom.A:
+				if box.URL == "" {
+					box.URL = attr(c, "href")
+				}
+			}
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	if box.Title == "" {
+		box.Title = box.URL
+	}
+	return box
+}
+
+// parseTable 将表格解析为字符串矩阵
+func parseTable(n *html.Node) Block {
+	table := Block{Kind: Table}
+
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.DataAtom == atom.Tr {
+			var row []string
+			for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.DataAtom == atom.Th || cell.DataAtom == atom.Td {
+					row = append(row, strings.TrimSpace(PlainText(parseInlines(cell))))
+				}
+			}
+			table.Rows = append(table.Rows, row)
+			return
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return table
+}
+
+func parseInlines(n *html.Node) []Inline {
+	var inlines []Inline
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		inlines = append(inlines, parseInline(c)...)
+	}
+	return inlines
+}
+
+func parseInline(n *html.Node) []Inline {
+	if n.Type == html.TextNode {
+		text := spaceRe.ReplaceAllString(n.Data, " ")
+		if text == "" {
+			return nil
+		}
+		return []Inline{{Kind: Text, Text: text}}
+	}
+	if n.Type != html.ElementNode {
+		return nil
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		return []Inline{{Kind: LineBreak}}
+	case atom.Strong, atom.B:
+		return []Inline{{Kind: Bold, Children: parseInlines(n)}}
+	case atom.Em, atom.I:
+		return []Inline{{Kind: Italic, Children: parseInlines(n)}}
+	case atom.Del, atom.S, atom.Strike:
+		return []Inline{{Kind: Strike, Children: parseInlines(n)}}
+	case atom.Code:
+		return []Inline{{Kind: Code, Text: textContent(n)}}
+	case atom.Img:
+		return parseImage(n, "")
+	case atom.A:
+		href := attr(n, "href")
+		switch {
+		case hasClass(n, "mention"), hasClass(n, "mention-group"):
+			return []Inline{{Kind: Mention, Text: strings.TrimPrefix(strings.TrimSpace(textContent(n)), "@"), URL: href}}
+		case hasClass(n, "lightbox"):
+			if img := findDescendant(n, atom.Img); img != nil {
+				return parseImage(img, href)
+			}
+		case hasClass(n, "anchor"):
+			return nil
+		}
+		return []Inline{{Kind: Link, URL: href, Children: parseInlines(n)}}
+	case atom.Span:
+		if hasClass(n, "spoiler") {
+			return []Inline{{Kind: Spoiler, Children: parseInlines(n)}}
+		}
+	case atom.Svg, atom.Script, atom.Style, atom.Noscript:
+		return nil
+	}
+
+	// 行内上下文中出现的块级元素，内容后补一个换行
+	inlines := parseInlines(n)
+	if isBlockNode(n) && len(inlines) > 0 {
+		inlines = append(inlines, Inline{Kind: LineBreak})
+	}
+	return inlines
+}
+
+// parseImage 解析图片，表情图片转换为 Unicode，头像直接忽略
+func parseImage(n *html.Node, href string) []Inline {
+	alt := attr(n, "alt")
+	switch {
+	case hasClass(n, "emoji"):
+		return []Inline{{Kind: Emoji, Text: emoji.Replace(alt)}}
+	case hasClass(n, "avatar"), hasClass(n, "site-icon"):
+		return nil
+	}
+
+	img := Inline{Kind: Image, Text: alt, URL: attr(n, "src")}
+	if href != "" {
+		img.URL = href
+	}
+	img.Width, _ = strconv.Atoi(attr(n, "width"))
+	img.Height, _ = strconv.Atoi(attr(n, "height"))
+	return []Inline{img}
+}
+
+// codeLang 从 Discourse 的 lang-xxx 类名中提取代码语言
+func codeLang(n *html.Node) string {
+	for _, class := range strings.Fields(attr(n, "class")) {
+		if lang := strings.TrimPrefix(class, "lang-"); lang != class && lang != "auto" && lang != "nohighlight" {
+			return lang
+		}
+	}
+	return ""
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(attr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func findChild(n *html.Node, a atom.Atom) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom == a {
+			return c
+		}
+	}
+	return nil
+}
+
+func findDescendant(n *html.Node, a atom.Atom) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom == a {
+			return c
+		}
+		if d := findDescendant(c, a); d != nil {
+			return d
+		}
+	}
+	return nil
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var s strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		s.WriteString(textContent(c))
+	}
+	return s.String()
+}
+
+// isBlank 判断行内内容是否只有空白
+func isBlank(inlines []Inline) bool {
+	for _, in := range inlines {
+		if in.Kind != LineBreak && (in.Kind != Text || strings.TrimSpace(in.Text) != "") {
+			return false
+		}
+	}
+	return true
+}
+
+// trimInlines 去掉首尾的空白和换行
+func trimInlines(inlines []Inline) []Inline {
+	for len(inlines) > 0 && (inlines[0].Kind == LineBreak || inlines[0].Kind == Text && strings.TrimSpace(inlines[0].Text) == "") {
+		inlines = inlines[1:]
+	}
+	for len(inlines) > 0 {
+		last := inlines[len(inlines)-1]
+		if last.Kind != LineBreak && (last.Kind != Text || strings.TrimSpace(last.Text) != "") {
+			break
+		}
+		inlines = inlines[:len(inlines)-1]
+	}
+	for i := range inlines {
+		// 行首的空白没有意义，换行前后的空格一并去掉
+		if inlines[i].Kind != Text {
+			continue
+		}
+		if i == 0 || inlines[i-1].Kind == LineBreak {
+			inlines[i].Text = strings.TrimLeft(inlines[i].Text, " ")
+		}
+		if i+1 < len(inlines) && inlines[i+1].Kind == LineBreak {
+			inlines[i].Text = strings.TrimRight(inlines[i].Text, " ")
+		}
+	}
+	if n := len(inlines); n > 0 && inlines[n-1].Kind == Text {
+		inlines[n-1].Text = strings.TrimRight(inlines[n-1].Text, " ")
+	}
+	return inlines
+}
@@ -0,0 +1,468 @@
+package render
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lhpqaq/ldo/internal/emoji"
+)
+
+var (
+	mdHeadingRe   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	mdListRe      = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdRuleRe      = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	mdTableSepRe  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	mdQuoteOpenRe = regexp.MustCompile(`^\[quote(?:="([^",\]]*)[^\]]*)?\]\s*$`)
+	mdDetailsRe   = regexp.MustCompile(`^\[details(?:="([^"]*)")?\]\s*$`)
+	mdImageSizeRe = regexp.MustCompile(`^(.*)\|(\d+)x(\d+)(?:,\s*\d+%)?$`)
+	mdMentionRe   = regexp.MustCompile(`^@([\w.-]+)`)
+	mdEmojiRe     = regexp.MustCompile(`^:([\w+-]+):`)
+	mdAutoLinkRe  = regexp.MustCompile(`^https?://[^\s<>()]+[^\s<>().,;:!?'"]`)
+)
+
+// ParseMarkdown 将 Discourse 风格的 Markdown 解析为文档树，用于发送前预览
+// 与 Discourse 一致，段落内的单个换行会保留为换行
+func ParseMarkdown(text string) *Document {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return &Document{Blocks: parseMarkdownBlocks(strings.Split(text, "\n"))}
+}
+
+func parseMarkdownBlocks(lines []string) []Block {
+	var blocks []Block
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence := trimmed[:3]
+			block := Block{Kind: CodeBlock, Lang: strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1]))}
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			i++
+			block.Text = strings.Join(code, "\n")
+			blocks = append(blocks, block)
+
+		case mdHeadingRe.MatchString(trimmed):
+			m := mdHeadingRe.FindStringSubmatch(trimmed)
+			blocks = append(blocks, Block{Kind: Heading, Level: len(m[1]), Inlines: parseMarkdownInlines(m[2])})
+			i++
+
+		case mdRuleRe.MatchString(trimmed):
+			blocks = append(blocks, Block{Kind: Rule})
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				l := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(l, " "))
+			}
+			blocks = append(blocks, Block{Kind: Quote, Children: parseMarkdownBlocks(quoted)})
+
+		case mdQuoteOpenRe.MatchString(trimmed):
+			m := mdQuoteOpenRe.FindStringSubmatch(trimmed)
+			inner, next := collectBBCode(lines, i, "quote")
+			blocks = append(blocks, Block{Kind: Quote, Title: strings.TrimSpace(m[1]), Children: parseMarkdownBlocks(inner)})
+			i = next
+
+		case mdDetailsRe.MatchString(trimmed):
+			m := mdDetailsRe.FindStringSubmatch(trimmed)
+			inner, next := collectBBCode(lines, i, "details")
+			blocks = append(blocks, Block{Kind: Details, Title: m[1], Children: parseMarkdownBlocks(inner)})
+			i = next
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && mdTableSepRe.MatchString(lines[i+1]):
+			table := Block{Kind: Table, Rows: [][]string{splitTableRow(trimmed)}}
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				table.Rows = append(table.Rows, splitTableRow(strings.TrimSpace(lines[i])))
+			}
+			blocks = append(blocks, table)
+
+		case mdListRe.MatchString(line):
+			var list Block
+			list, i = parseMarkdownList(lines, i)
+			blocks = append(blocks, list)
+
+		default:
+			var inlines []Inline
+			for ; i < len(lines) && !startsMarkdownBlock(lines, i); i++ {
+				if len(inlines) > 0 {
+					inlines = append(inlines, Inline{Kind: LineBreak})
+				}
+				inlines = append(inlines, parseMarkdownInlines(strings.TrimSpace(lines[i]))...)
+			}
+			blocks = append(blocks, Block{Kind: Paragraph, Inlines: inlines})
+		}
+	}
+
+	return blocks
+}
+
+// startsMarkdownBlock 判断第 i 行是否结束当前段落
+func startsMarkdownBlock(lines []string, i int) bool {
+	trimmed := strings.TrimSpace(lines[i])
+	return trimmed == "" ||
+		strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") ||
+		strings.HasPrefix(trimmed, ">") ||
+		mdHeadingRe.MatchString(trimmed) ||
+		mdQuoteOpenRe.MatchString(trimmed) ||
+		mdDetailsRe.MatchString(trimmed) ||
+		mdListRe.MatchString(lines[i])
+}
+
+// collectBBCode 收集 [tag]...[/tag] 之间的行，支持嵌套，返回内容和下一行的位置
+func collectBBCode(lines []string, start int, tag string) ([]string, int) {
+	depth := 0
+	var inner []string
+	for i := start; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "["+tag) {
+			depth++
+			if depth == 1 {
+				continue
+			}
+		}
+		if strings.HasPrefix(trimmed, "[/"+tag+"]") {
+			depth--
+			if depth == 0 {
+				return inner, i + 1
+			}
+		}
+		inner = append(inner, lines[i])
+	}
+	return inner, len(lines)
+}
+
+func splitTableRow(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	var cells []string
+	for _, cell := range strings.Split(line, "|") {
+		cells = append(cells, PlainText(parseMarkdownInlines(strings.TrimSpace(cell))))
+	}
+	return cells
+}
+
+// parseMarkdownList 解析从第 start 行开始的列表，缩进更深的行归入当前项
+func parseMarkdownList(lines []string, start int) (Block, int) {
+	m := mdListRe.FindStringSubmatch(lines[start])
+	baseIndent := len(m[1])
+	list := Block{Kind: List, Start: 1}
+	if n, err := strconv.Atoi(strings.TrimRight(m[2], ".)")); err == nil {
+		list.Ordered = true
+		list.Start = n
+	}
+
+	var item []string
+	flush := func() {
+		if item != nil {
+			list.Items = append(list.Items, parseMarkdownBlocks(item))
+		}
+		item = nil
+	}
+
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if strings.TrimSpace(line) == "" {
+			// 空行之后仍是缩进内容或同级列表项时列表继续
+			if i+1 < len(lines) && (leadingSpaces(lines[i+1]) > baseIndent || mdListRe.MatchString(lines[i+1])) {
+				item = append(item, "")
+				continue
+			}
+			break
+		}
+
+		if mm := mdListRe.FindStringSubmatch(line); mm != nil && len(mm[1]) == baseIndent {
+			if _, err := strconv.Atoi(strings.TrimRight(mm[2], ".)")); (err == nil) != list.Ordered {
+				// 有序与无序列表交替出现时视为新的列表
+				break
+			}
+			flush()
+			item = []string{mm[3]}
+			continue
+		}
+		if indent <= baseIndent && startsMarkdownBlock(lines, i) {
+			break
+		}
+
+		// 续行和嵌套内容去掉一级缩进
+		strip := min(indent, baseIndent+len(m[2])+1)
+		item = append(item, line[strip:])
+	}
+	flush()
+	return list, i
+}
+
+func leadingSpaces(line string) int {
+	if strings.TrimSpace(line) == "" {
+		return 0
+	}
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// parseMarkdownInlines 解析行内标记：代码、链接、图片、强调、删除线、剧透、提及和表情
+func parseMarkdownInlines(text string) []Inline {
+	var inlines []Inline
+	var plain strings.Builder
+
+	flushText := func() {
+		if plain.Len() > 0 {
+			inlines = append(inlines, Inline{Kind: Text, Text: plain.String()})
+			plain.Reset()
+		}
+	}
+	emit := func(in Inline) {
+		flushText()
+		inlines = append(inlines, in)
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		prevWord := i > 0 && isWordByte(text[i-1])
+
+		switch {
+		case rest[0] == '`':
+			if end := strings.Index(rest[1:], "`"); end >= 0 {
+				emit(Inline{Kind: Code, Text: rest[1 : end+1]})
+				i += end + 2
+				continue
+			}
+
+		case strings.HasPrefix(rest, "!["):
+			if alt, url, n, ok := parseLinkSyntax(rest[1:]); ok {
+				img := Inline{Kind: Image, Text: alt, URL: url}
+				if m := mdImageSizeRe.FindStringSubmatch(alt); m != nil {
+					img.Text = m[1]
+					img.Width, _ = strconv.Atoi(m[2])
+					img.Height, _ = strconv.Atoi(m[3])
+				}
+				emit(img)
+				i += n + 1
+				continue
+			}
+
+		case strings.HasPrefix(rest, "[spoiler]"):
+			if end := strings.Index(rest, "[/spoiler]"); end >= 0 {
+				emit(Inline{Kind: Spoiler, Children: parseMarkdownInlines(rest[len("[spoiler]"):end])})
+				i += end + len("[/spoiler]")
+				continue
+			}
+
+		case rest[0] == '[':
+			if label, url, n, ok := parseLinkSyntax(rest); ok {
+				emit(Inline{Kind: Link, URL: url, Children: parseMarkdownInlines(label)})
+				i += n
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if end := strings.Index(rest[2:], rest[:2]); end > 0 {
+				emit(Inline{Kind: Bold, Children: parseMarkdownInlines(rest[2 : end+2])})
+				i += end + 4
+				continue
+			}
+
+		case strings.HasPrefix(rest, "~~"):
+			if end := strings.Index(rest[2:], "~~"); end > 0 {
+				emit(Inline{Kind: Strike, Children: parseMarkdownInlines(rest[2 : end+2])})
+				i += end + 4
+				continue
+			}
+
+		case (rest[0] == '*' || rest[0] == '_' && !prevWord) && len(rest) > 1 && rest[1] != ' ':
+			if end := strings.IndexByte(rest[1:], rest[0]); end > 0 && rest[end] != ' ' {
+				after := end + 2
+				if rest[0] == '*' || after >= len(rest) || !isWordByte(rest[after]) {
+					emit(Inline{Kind: Italic, Children: parseMarkdownInlines(rest[1 : end+1])})
+					i += after
+					continue
+				}
+			}
+
+		case rest[0] == '@' && !prevWord:
+			if m := mdMentionRe.FindStringSubmatch(rest); m != nil {
+				emit(Inline{Kind: Mention, Text: m[1], URL: "/u/" + m[1]})
+				i += len(m[0])
+				continue
+			}
+
+		case rest[0] == ':':
+			if m := mdEmojiRe.FindStringSubmatch(rest); m != nil {
+				if e, ok := emoji.Lookup(m[1]); ok {
+					emit(Inline{Kind: Emoji, Text: e})
+					i += len(m[0])
+					continue
+				}
+			}
+
+		case rest[0] == 'h' && !prevWord:
+			if m := mdAutoLinkRe.FindString(rest); m != "" {
+				emit(Inline{Kind: Link, URL: m})
+				i += len(m)
+				continue
+			}
+		}
+
+		plain.WriteByte(text[i])
+		i++
+	}
+	flushText()
+	return inlines
+}
+
+// parseLinkSyntax 解析 [label](url "title")，返回标签、地址和消耗的字节数
+func parseLinkSyntax(s string) (string, string, int, bool) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				if i+1 >= len(s) || s[i+1] != '(' {
+					return "", "", 0, false
+				}
+				end := strings.IndexByte(s[i+2:], ')')
+				if end < 0 {
+					return "", "", 0, false
+				}
+				target := strings.Fields(s[i+2 : i+2+end])
+				if len(target) == 0 {
+					return "", "", 0, false
+				}
+				return s[1:i], target[0], i + 3 + end, true
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// Markdown 将文档树渲染为 Markdown，用于引用和导出
+func Markdown(doc *Document) string {
+	var s strings.Builder
+	writeMarkdownBlocks(&s, doc.Blocks, "", true)
+	return strings.TrimSpace(s.String())
+}
+
+// writeMarkdownBlocks 输出块级节点；loose 为 true 时块之间空一行
+func writeMarkdownBlocks(s *strings.Builder, blocks []Block, prefix string, loose bool) {
+	for i, b := range blocks {
+		if i > 0 && loose {
+			s.WriteString(strings.TrimRight(prefix, " ") + "\n")
+		}
+		writeMarkdownBlock(s, b, prefix)
+	}
+}
+
+func writeMarkdownBlock(s *strings.Builder, b Block, prefix string) {
+	writeLines := func(text string) {
+		for _, line := range strings.Split(text, "\n") {
+			s.WriteString(prefix + line + "\n")
+		}
+	}
+
+	switch b.Kind {
+	case Paragraph:
+		writeLines(markdownInlines(b.Inlines))
+	case Heading:
+		writeLines(strings.Repeat("#", b.Level) + " " + markdownInlines(b.Inlines))
+	case Rule:
+		writeLines("---")
+	case CodeBlock:
+		writeLines("```" + b.Lang + "\n" + b.Text + "\n```")
+	case Quote:
+		if b.Title != "" {
+			writeLines(fmt.Sprintf("[quote=\"%s\"]", b.Title))
+			writeMarkdownBlocks(s, b.Children, prefix, true)
+			writeLines("[/quote]")
+		} else {
+			writeMarkdownBlocks(s, b.Children, prefix+"> ", true)
+		}
+	case Details:
+		writeLines(fmt.Sprintf("[details=\"%s\"]", b.Title))
+		writeMarkdownBlocks(s, b.Children, prefix, true)
+		writeLines("[/details]")
+	case Onebox:
+		writeLines(b.URL)
+	case Table:
+		for i, row := range b.Rows {
+			writeLines("| " + strings.Join(row, " | ") + " |")
+			if i == 0 {
+				writeLines(strings.TrimSuffix(strings.Repeat("| --- ", len(row)), " ") + " |")
+			}
+		}
+	case List:
+		for i, item := range b.Items {
+			marker := "- "
+			if b.Ordered {
+				marker = fmt.Sprintf("%d. ", b.Start+i)
+			}
+			var inner strings.Builder
+			writeMarkdownBlocks(&inner, item, "", false)
+			indent := strings.Repeat(" ", len(marker))
+			for j, line := range strings.Split(strings.TrimRight(inner.String(), "\n"), "\n") {
+				if j == 0 {
+					s.WriteString(prefix + marker + line + "\n")
+				} else if line == "" {
+					s.WriteString(strings.TrimRight(prefix, " ") + "\n")
+				} else {
+					s.WriteString(prefix + indent + line + "\n")
+				}
+			}
+		}
+	}
+}
+
+func markdownInlines(inlines []Inline) string {
+	var s strings.Builder
+	for _, in := range inlines {
+		switch in.Kind {
+		case Text, Emoji:
+			s.WriteString(in.Text)
+		case Bold:
+			s.WriteString("**" + markdownInlines(in.Children) + "**")
+		case Italic:
+			s.WriteString("*" + markdownInlines(in.Children) + "*")
+		case Strike:
+			s.WriteString("~~" + markdownInlines(in.Children) + "~~")
+		case Code:
+			s.WriteString("`" + in.Text + "`")
+		case Link:
+			label := markdownInlines(in.Children)
+			if label == "" || label == in.URL {
+				s.WriteString(in.URL)
+			} else {
+				s.WriteString("[" + label + "](" + in.URL + ")")
+			}
+		case Mention:
+			s.WriteString("@" + in.Text)
+		case Image:
+			alt := in.Text
+			if in.Width > 0 && in.Height > 0 {
+				alt = fmt.Sprintf("%s|%dx%d", alt, in.Width, in.Height)
+			}
+			s.WriteString("![" + alt + "](" + in.URL + ")")
+		case Spoiler:
+			s.WriteString("[spoiler]" + markdownInlines(in.Children) + "[/spoiler]")
+		case LineBreak:
+			s.WriteString("\n")
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/theme"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "用当前输出更新 testdata 下的 golden 文件")
+
+// goldenWidth golden 文件使用的折行宽度，足够窄以覆盖折行和表格截断
+const goldenWidth = 60
+
+func TestGolden(t *testing.T) {
+	// 固定配色，使样式输出不依赖运行测试的终端
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	SetTheme(theme.Dark)
+
+	samples, err := filepath.Glob(filepath.Join("testdata", "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) == 0 {
+		t.Fatal("testdata 中没有样例")
+	}
+
+	outputs := []struct {
+		suffix string
+		render func(*Document) string
+	}{
+		{".styled.golden", func(doc *Document) string {
+			return Styled(doc, Options{Width: goldenWidth, Highlight: true})
+		}},
+		{".plain.golden", func(doc *Document) string {
+			return Plain(doc, Options{Width: goldenWidth})
+		}},
+		{".md.golden", Markdown},
+	}
+
+	for _, sample := range samples {
+		cooked, err := os.ReadFile(sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+		base := strings.TrimSuffix(sample, ".html")
+		for _, out := range outputs {
+			name := filepath.Base(base) + out.suffix
+			t.Run(name, func(t *testing.T) {
+				// 每次重新解析，避免渲染之间共享解析结果
+				got := out.render(Parse(string(cooked)))
+				checkGolden(t, base+out.suffix, got)
+			})
+		}
+	}
+}
+
+func checkGolden(t *testing.T, path, got string) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取 golden 文件失败（使用 -update 生成）: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("输出与 %s 不一致（确认无误后使用 -update 更新）\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,703 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/theme"
+	"github.com/mattn/go-runewidth"
+)
+
+// 样式随主题变化，由 SetTheme 设置
+var (
+	headingStyle    lipgloss.Style
+	codeBlockStyle  lipgloss.Style
+	inlineCodeStyle lipgloss.Style
+	linkStyle       lipgloss.Style
+	mentionStyle    lipgloss.Style
+	mutedStyle      lipgloss.Style
+	spoilerStyle    lipgloss.Style
+	markStyle       lipgloss.Style
+)
+
+func init() {
+	SetTheme(theme.Dark)
+}
+
+// SetTheme 切换样式输出使用的配色
+func SetTheme(t theme.Theme) {
+	headingStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Primary)
+	codeBlockStyle = lipgloss.NewStyle().Foreground(t.Code)
+	inlineCodeStyle = lipgloss.NewStyle().Foreground(t.Code).Background(t.CodeBg)
+	linkStyle = lipgloss.NewStyle().Foreground(t.Link).Underline(true)
+	mentionStyle = lipgloss.NewStyle().Foreground(t.Mention).Bold(true)
+	mutedStyle = lipgloss.NewStyle().Foreground(t.Muted)
+	// 剧透内容前景色与背景色相同，选中文字即可查看
+	spoilerStyle = lipgloss.NewStyle().Foreground(t.Spoiler).Background(t.Spoiler)
+	markStyle = lipgloss.NewStyle().Foreground(t.OnMark).Background(t.Mark)
+
+	if t.Monochrome {
+		inlineCodeStyle = inlineCodeStyle.Bold(true)
+		spoilerStyle = spoilerStyle.Faint(true)
+		markStyle = markStyle.Reverse(true)
+	}
+}
+
+// Options 渲染选项
+type Options struct {
+	Width     int    // 折行宽度，0 表示不折行
+	Highlight bool   // 是否对代码块做语法高亮
+	CodeStyle string // 语法高亮配色方案（chroma 样式名），为空时使用 DefaultCodeStyle
+	Mark      string // 需要高亮标记的关键词（不区分大小写），仅样式输出有效
+
+	// Image 返回单独成段的图片在占位文本下方显示的内容，为 nil 或返回空时只显示占位文本
+	Image func(img Inline, width int) []string
+}
+
+// LinkSpan 渲染结果中一段链接文字的位置，链接折行后每行各有一段
+type LinkSpan struct {
+	Line  int // 行号，从 0 开始
+	Start int // 起始列（显示宽度）
+	End   int // 结束列，不包含
+	Link  int // 链接在 Document.Links() 中的下标
+}
+
+// Styled 渲染为带 ANSI 样式的终端文本，用于 TUI
+func Styled(doc *Document, opts Options) string {
+	text, _ := StyledLinks(doc, opts)
+	return text
+}
+
+// StyledLinks 与 Styled 相同，同时返回链接文字在渲染结果中的位置，用于按点击位置打开链接
+func StyledLinks(doc *Document, opts Options) (string, []LinkSpan) {
+	r := &renderer{styled: true, opts: opts, links: make(map[string]int)}
+	for i, link := range doc.Links() {
+		r.links[link.URL] = i + 1
+	}
+	text := strings.Join(r.blocks(doc.Blocks, opts.Width, true), "\n")
+	return text, r.spans
+}
+
+// Plain 渲染为纯文本，用于 CLI；开启 Highlight 时代码块仍会带颜色
+func Plain(doc *Document, opts Options) string {
+	r := &renderer{opts: opts}
+	return strings.Join(r.blocks(doc.Blocks, opts.Width, true), "\n")
+}
+
+type renderer struct {
+	styled    bool
+	opts      Options
+	listDepth int
+
+	links map[string]int // 链接地址 -> 在 Document.Links() 中的下标加 1，只有 StyledLinks 会设置
+	spans []LinkSpan
+}
+
+// segment 同一样式的一段文本；newline 表示强制换行；link 为所属链接的下标加 1，0 表示不是链接
+type segment struct {
+	text    string
+	style   lipgloss.Style
+	newline bool
+	link    int
+}
+
+// shift 将 from 之后记录的链接位置移动到外层的行列，用于子块加上缩进、拼接到外层之后
+func (r *renderer) shift(from, line, col int) {
+	for i := from; i < len(r.spans); i++ {
+		r.spans[i].Line += line
+		r.spans[i].Start += col
+		r.spans[i].End += col
+	}
+}
+
+// label 样式输出面向 TUI 使用中文，纯文本输出面向 CLI 使用英文
+func (r *renderer) label(zh, en string) string {
+	if r.styled {
+		return zh
+	}
+	return en
+}
+
+func (r *renderer) render(text string, style lipgloss.Style) string {
+	if !r.styled || text == "" {
+		return text
+	}
+	return style.Render(text)
+}
+
+// blocks 渲染块级节点；loose 为 true 时块之间空一行
+func (r *renderer) blocks(blocks []Block, width int, loose bool) []string {
+	var lines []string
+	for i, b := range blocks {
+		if i > 0 && loose {
+			lines = append(lines, "")
+		}
+		from := len(r.spans)
+		block := r.block(b, width)
+		r.shift(from, len(lines), 0)
+		lines = append(lines, block...)
+	}
+	return lines
+}
+
+func (r *renderer) block(b Block, width int) []string {
+	switch b.Kind {
+	case Paragraph:
+		if images := imagesOnly(b.Inlines); images != nil && r.opts.Image != nil {
+			var lines []string
+			for _, img := range images {
+				lines = append(lines, r.render(r.imagePlaceholder(img), mutedStyle))
+				lines = append(lines, r.opts.Image(img, width)...)
+			}
+			return lines
+		}
+		return r.wrap(r.segments(b.Inlines, lipgloss.NewStyle()), width)
+
+	case Heading:
+		segs := r.segments(b.Inlines, headingStyle.Copy())
+		if !r.styled {
+			segs = append([]segment{{text: strings.Repeat("#", b.Level) + " "}}, segs...)
+		}
+		return r.wrap(segs, width)
+
+	case Rule:
+		if r.styled {
+			return []string{r.render(strings.Repeat("─", ruleWidth(width)), mutedStyle)}
+		}
+		return []string{strings.Repeat("-", ruleWidth(width))}
+
+	case CodeBlock:
+		return r.codeBlock(b, width)
+
+	case Quote:
+		bar := r.render("│ ", mutedStyle)
+		if !r.styled {
+			bar = "> "
+		}
+		var lines []string
+		if b.Title != "" {
+			lines = append(lines, bar+r.render("@"+b.Title+r.label(":", " wrote:"), mentionStyle))
+		}
+		from := len(r.spans)
+		children := r.blocks(b.Children, width-2, true)
+		r.shift(from, len(lines), 2)
+		for _, line := range children {
+			lines = append(lines, strings.TrimRight(bar+line, " "))
+		}
+		return lines
+
+	case Details:
+		lines := []string{r.render("▼ "+b.Title, lipgloss.NewStyle().Bold(true))}
+		from := len(r.spans)
+		children := r.blocks(b.Children, width-2, true)
+		r.shift(from, len(lines), 2)
+		for _, line := range children {
+			lines = append(lines, indentLine("  ", line))
+		}
+		return lines
+
+	case Onebox:
+		return r.onebox(b, width)
+
+	case Table:
+		return r.table(b, width)
+
+	case List:
+		return r.list(b, width)
+	}
+	return nil
+}
+
+func (r *renderer) list(b Block, width int) []string {
+	bullets := []string{"•", "◦", "▪"}
+	r.listDepth++
+	defer func() { r.listDepth-- }()
+
+	var lines []string
+	for i, item := range b.Items {
+		marker := bullets[(r.listDepth-1)%len(bullets)]
+		if b.Ordered {
+			marker = fmt.Sprintf("%d.", b.Start+i)
+		}
+		indent := strings.Repeat(" ", runewidth.StringWidth(marker)+1)
+
+		from := len(r.spans)
+		itemLines := r.blocks(item, width-len(indent), false)
+		r.shift(from, len(lines), len(indent))
+		if len(itemLines) == 0 {
+			itemLines = []string{""}
+		}
+		for j, line := range itemLines {
+			if j == 0 {
+				lines = append(lines, r.render(marker, mutedStyle)+" "+line)
+			} else {
+				lines = append(lines, indentLine(indent, line))
+			}
+		}
+	}
+	return lines
+}
+
+func (r *renderer) codeBlock(b Block, width int) []string {
+	text := strings.ReplaceAll(b.Text, "\t", "    ")
+
+	var code [][]segment
+	if r.opts.Highlight {
+		code = highlightCode(text, b.Lang, r.opts.CodeStyle)
+	}
+	highlighted := code != nil
+	if !highlighted {
+		for _, line := range strings.Split(text, "\n") {
+			code = append(code, []segment{{text: line, style: codeBlockStyle}})
+		}
+	}
+
+	// 纯文本模式只有在开启高亮时才输出颜色
+	paint := func(segs []segment) string {
+		var s strings.Builder
+		for _, seg := range segs {
+			if highlighted {
+				s.WriteString(seg.style.Render(seg.text))
+			} else {
+				s.WriteString(r.render(seg.text, seg.style))
+			}
+		}
+		return s.String()
+	}
+
+	if !r.styled {
+		lines := []string{"```" + b.Lang}
+		for _, line := range code {
+			lines = append(lines, paint(line))
+		}
+		return append(lines, "```")
+	}
+
+	header := "┌─"
+	if b.Lang != "" {
+		header += " " + b.Lang
+	}
+	lines := []string{r.render(header, mutedStyle)}
+	for _, line := range code {
+		for _, part := range wrapCode(r.mark(line), width-2) {
+			lines = append(lines, r.render("│ ", mutedStyle)+paint(part))
+		}
+	}
+	return append(lines, r.render("└─", mutedStyle))
+}
+
+func (r *renderer) onebox(b Block, width int) []string {
+	bar := r.render("│ ", mutedStyle)
+	if !r.styled {
+		bar = "  "
+	}
+
+	icon := "🔗 "
+	lines := []string{icon + r.render(b.Title, linkStyle.Copy().Bold(true))}
+	if link := r.links[b.URL]; link > 0 && b.Title != "" {
+		start := runewidth.StringWidth(icon)
+		r.spans = append(r.spans, LinkSpan{Start: start, End: start + runewidth.StringWidth(b.Title), Link: link - 1})
+	}
+	if b.URL != "" && b.URL != b.Title {
+		for _, line := range hardWrap(b.URL, width-2) {
+			lines = append(lines, bar+r.render(line, mutedStyle))
+		}
+	}
+	if b.Text != "" {
+		desc := r.wrap([]segment{{text: b.Text}}, width-2)
+		// 摘要最多显示三行
+		if len(desc) > 3 {
+			desc = append(desc[:2], runewidth.Truncate(desc[2], max(width-3, 10), "…"))
+		}
+		for _, line := range desc {
+			lines = append(lines, bar+line)
+		}
+	}
+	return lines
+}
+
+func (r *renderer) table(b Block, width int) []string {
+	if len(b.Rows) == 0 {
+		return nil
+	}
+
+	cols := 0
+	for _, row := range b.Rows {
+		cols = max(cols, len(row))
+	}
+	widths := make([]int, cols)
+	for _, row := range b.Rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], runewidth.StringWidth(cell))
+		}
+	}
+	total := 3 * (cols - 1)
+	for _, w := range widths {
+		total += w
+	}
+
+	sep, cross, line := " │ ", "─┼─", "─"
+	if !r.styled {
+		sep, cross, line = " | ", "-+-", "-"
+	}
+
+	// 终端放不下时改为逐行列出“表头: 值”
+	if width > 0 && total > width {
+		var lines []string
+		header := b.Rows[0]
+		for i, row := range b.Rows[1:] {
+			if i > 0 {
+				lines = append(lines, r.render(strings.Repeat(line, ruleWidth(width)/2), mutedStyle))
+			}
+			for j, cell := range row {
+				name := ""
+				if j < len(header) {
+					name = header[j]
+				}
+				lines = append(lines, r.wrap([]segment{
+					{text: name + ": ", style: lipgloss.NewStyle().Bold(true)},
+					{text: cell},
+				}, width)...)
+			}
+		}
+		return lines
+	}
+
+	var lines []string
+	for i, row := range b.Rows {
+		cells := make([]string, cols)
+		for j := range cells {
+			cell := ""
+			if j < len(row) {
+				cell = row[j]
+			}
+			cell = runewidth.FillRight(cell, widths[j])
+			if i == 0 {
+				cell = r.render(cell, lipgloss.NewStyle().Bold(true))
+			}
+			cells[j] = cell
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, r.render(sep, mutedStyle)), " "))
+
+		if i == 0 {
+			rules := make([]string, cols)
+			for j, w := range widths {
+				rules[j] = strings.Repeat(line, w)
+			}
+			lines = append(lines, r.render(strings.Join(rules, cross), mutedStyle))
+		}
+	}
+	return lines
+}
+
+// segments 将行内节点展开为带样式的文本段
+func (r *renderer) segments(inlines []Inline, style lipgloss.Style) []segment {
+	var segs []segment
+	for _, in := range inlines {
+		switch in.Kind {
+		case Text, Emoji:
+			segs = append(segs, segment{text: in.Text, style: style})
+		case LineBreak:
+			segs = append(segs, segment{newline: true})
+		case Bold:
+			segs = append(segs, r.segments(in.Children, style.Copy().Bold(true))...)
+		case Italic:
+			segs = append(segs, r.segments(in.Children, style.Copy().Italic(true))...)
+		case Strike:
+			segs = append(segs, r.segments(in.Children, style.Copy().Strikethrough(true))...)
+		case Code:
+			if r.styled {
+				segs = append(segs, segment{text: in.Text, style: inlineCodeStyle})
+			} else {
+				segs = append(segs, segment{text: "`" + in.Text + "`"})
+			}
+		case Mention:
+			segs = append(segs, segment{text: "@" + in.Text, style: mentionStyle})
+		case Link:
+			label := r.segments(in.Children, linkStyle.Copy().Inherit(style))
+			text := PlainText(in.Children)
+			if len(label) == 0 || strings.TrimSpace(text) == "" {
+				label = []segment{{text: in.URL, style: linkStyle}}
+			}
+			for i := range label {
+				label[i].link = r.links[in.URL]
+			}
+			segs = append(segs, label...)
+			// 纯文本模式下链接地址无法点击，附在文字后面
+			if !r.styled && text != "" && text != in.URL && in.URL != "" {
+				segs = append(segs, segment{text: " (" + in.URL + ")"})
+			}
+		case Image:
+			segs = append(segs, segment{text: r.imagePlaceholder(in), style: mutedStyle})
+		case Spoiler:
+			if r.styled {
+				segs = append(segs, r.segments(in.Children, spoilerStyle)...)
+			} else {
+				segs = append(segs, segment{text: "[spoiler: "})
+				segs = append(segs, r.segments(in.Children, style)...)
+				segs = append(segs, segment{text: "]"})
+			}
+		}
+	}
+	return segs
+}
+
+// imagesOnly 段落只包含图片时返回这些图片
+func imagesOnly(inlines []Inline) []Inline {
+	var images []Inline
+	for _, in := range inlines {
+		switch {
+		case in.Kind == Image:
+			images = append(images, in)
+		case in.Kind == LineBreak, in.Kind == Text && strings.TrimSpace(in.Text) == "":
+		default:
+			return nil
+		}
+	}
+	return images
+}
+
+// imagePlaceholder 图片占位文本，包含替代文本和尺寸
+func (r *renderer) imagePlaceholder(img Inline) string {
+	text := r.label("[图片", "[image")
+	if img.Text != "" {
+		text += ": " + img.Text
+	}
+	if img.Width > 0 && img.Height > 0 {
+		text += fmt.Sprintf(" %d×%d", img.Width, img.Height)
+	}
+	return text + "]"
+}
+
+// wrap 按显示宽度折行，英文按单词折行，中日韩文字可在任意字符处折行
+func (r *renderer) wrap(segs []segment, width int) []string {
+	type piece struct {
+		text string
+		src  int
+	}
+
+	segs = r.mark(segs)
+	from := len(r.spans)
+
+	var lines [][]piece
+	var line []piece
+	lineWidth := 0
+
+	newLine := func() {
+		if n := len(line); n > 0 && line[n-1].text == " " {
+			line = line[:n-1]
+		}
+		lines = append(lines, line)
+		line = nil
+		lineWidth = 0
+	}
+	add := func(text string, src int) {
+		line = append(line, piece{text, src})
+		lineWidth += runewidth.StringWidth(text)
+	}
+
+	for i, seg := range segs {
+		if seg.newline {
+			newLine()
+			continue
+		}
+		for _, tok := range tokenize(seg.text) {
+			w := runewidth.StringWidth(tok)
+			if tok == " " {
+				if lineWidth == 0 {
+					continue
+				}
+				if width > 0 && lineWidth+1 > width {
+					newLine()
+					continue
+				}
+			} else if width > 0 && lineWidth+w > width {
+				if lineWidth > 0 {
+					newLine()
+				}
+				parts := hardWrap(tok, width)
+				for _, part := range parts[:len(parts)-1] {
+					add(part, i)
+					newLine()
+				}
+				tok = parts[len(parts)-1]
+			}
+			add(tok, i)
+		}
+	}
+	if len(line) > 0 {
+		newLine()
+	}
+
+	out := make([]string, len(lines))
+	for i, pieces := range lines {
+		// 记录链接文字所在的列，同一链接相邻的片段合并为一段
+		col := 0
+		for _, p := range pieces {
+			w := runewidth.StringWidth(p.text)
+			if link := segs[p.src].link; link > 0 {
+				if n := len(r.spans); n > from && r.spans[n-1].Line == i && r.spans[n-1].Link == link-1 && r.spans[n-1].End == col {
+					r.spans[n-1].End += w
+				} else {
+					r.spans = append(r.spans, LinkSpan{Line: i, Start: col, End: col + w, Link: link - 1})
+				}
+			}
+			col += w
+		}
+
+		var s strings.Builder
+		for j := 0; j < len(pieces); {
+			// 合并来自同一文本段的片段后统一上样式
+			k := j
+			var text strings.Builder
+			for ; k < len(pieces) && pieces[k].src == pieces[j].src; k++ {
+				text.WriteString(pieces[k].text)
+			}
+			s.WriteString(r.render(text.String(), segs[pieces[j].src].style))
+			j = k
+		}
+		out[i] = s.String()
+	}
+	return out
+}
+
+// mark 将文本段中与 Options.Mark 匹配的部分拆成单独的高亮段。
+// 在拼接后的文本中查找，关键词可以跨越不同样式的文本段（如一半加粗）
+func (r *renderer) mark(segs []segment) []segment {
+	keyword, _ := lowerWithOffsets(r.opts.Mark)
+	if !r.styled || keyword == "" {
+		return segs
+	}
+
+	var joined strings.Builder
+	for _, seg := range segs {
+		if seg.newline {
+			// 关键词不含换行，不会跨过强制换行匹配
+			joined.WriteString("\n")
+		} else {
+			joined.WriteString(seg.text)
+		}
+	}
+	text := joined.String()
+	lower, pos := lowerWithOffsets(text)
+
+	// 匹配位置换算回原文，转小写后字节长度变化的字符也能对应
+	var matches [][2]int
+	for i := 0; ; {
+		j := strings.Index(lower[i:], keyword)
+		if j < 0 {
+			break
+		}
+		start, end := i+j, i+j+len(keyword)
+		matches = append(matches, [2]int{pos[start], pos[end]})
+		i = end
+	}
+	if len(matches) == 0 {
+		return segs
+	}
+
+	var out []segment
+	offset := 0
+	for _, seg := range segs {
+		if seg.newline {
+			out = append(out, seg)
+			offset++
+			continue
+		}
+		cur, end := offset, offset+len(seg.text)
+		offset = end
+		for _, m := range matches {
+			if m[1] <= cur || m[0] >= end {
+				continue
+			}
+			if m[0] > cur {
+				out = append(out, segment{text: text[cur:m[0]], style: seg.style, link: seg.link})
+				cur = m[0]
+			}
+			stop := min(m[1], end)
+			out = append(out, segment{text: text[cur:stop], style: markStyle, link: seg.link})
+			cur = stop
+		}
+		if cur < end {
+			out = append(out, segment{text: text[cur:end], style: seg.style, link: seg.link})
+		}
+	}
+	return out
+}
+
+// lowerWithOffsets 逐个字符转为小写，同时返回小写文本中每个字节对应的原文位置，
+// 最后多一项为原文长度
+func lowerWithOffsets(s string) (string, []int) {
+	var b strings.Builder
+	pos := make([]int, 0, len(s)+1)
+	for i, r := range s {
+		n, _ := b.WriteRune(unicode.ToLower(r))
+		for k := 0; k < n; k++ {
+			pos = append(pos, i)
+		}
+	}
+	return b.String(), append(pos, len(s))
+}
+
+// tokenize 将文本拆分为单词、空格和单个宽字符
+func tokenize(text string) []string {
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+			tokens = append(tokens, " ")
+		case runewidth.RuneWidth(r) > 1:
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// hardWrap 按显示宽度强制切分过长的文本
+func hardWrap(text string, width int) []string {
+	if width <= 0 || runewidth.StringWidth(text) <= width {
+		return []string{text}
+	}
+	var parts []string
+	var cur strings.Builder
+	curWidth := 0
+	for _, r := range text {
+		w := runewidth.RuneWidth(r)
+		if curWidth+w > width && curWidth > 0 {
+			parts = append(parts, cur.String())
+			cur.Reset()
+			curWidth = 0
+		}
+		cur.WriteRune(r)
+		curWidth += w
+	}
+	return append(parts, cur.String())
+}
+
+func indentLine(indent, line string) string {
+	if line == "" {
+		return ""
+	}
+	return indent + line
+}
+
+func ruleWidth(width int) int {
+	if width <= 0 || width > 40 {
+		return 40
+	}
+	return width
+}
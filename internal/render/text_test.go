@@ -0,0 +1,175 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+)
+
+// spanText 返回不带样式的渲染结果中 span 覆盖的文字
+func spanText(lines []string, span LinkSpan) string {
+	if span.Line >= len(lines) {
+		return ""
+	}
+	var s strings.Builder
+	col := 0
+	for _, r := range lines[span.Line] {
+		if col >= span.Start && col < span.End {
+			s.WriteRune(r)
+		}
+		col += runewidth.RuneWidth(r)
+	}
+	return s.String()
+}
+
+func TestStyledLinks(t *testing.T) {
+	// 不输出样式，按列取出的文字即为屏幕上显示的文字
+	lipgloss.SetColorProfile(termenv.Ascii)
+	defer lipgloss.SetColorProfile(termenv.ANSI256)
+
+	type span struct {
+		line int
+		text string
+		link int
+	}
+	tests := []struct {
+		name   string
+		cooked string
+		width  int
+		mark   string
+		want   []span
+	}{
+		{
+			name:   "文字相同的链接",
+			cooked: `<p><a href="https://a.example">这里</a> 和 <a href="https://b.example">这里</a></p>`,
+			want:   []span{{0, "这里", 0}, {0, "这里", 1}},
+		},
+		{
+			name:   "折行的链接",
+			cooked: `<p>见 <a href="https://a.example">one two three four</a> 结束</p>`,
+			width:  12,
+			want:   []span{{0, "one two", 0}, {1, "three four", 0}},
+		},
+		{
+			name:   "链接中的搜索高亮",
+			cooked: `<p><a href="https://a.example">release notes</a></p>`,
+			mark:   "notes",
+			want:   []span{{0, "release notes", 0}},
+		},
+		{
+			name:   "链接中的加粗",
+			cooked: `<p>x <a href="https://a.example">a <strong>b</strong> c</a> y</p>`,
+			want:   []span{{0, "a b c", 0}},
+		},
+		{
+			name:   "重复的地址对应同一个链接",
+			cooked: `<p><a href="https://a.example">a</a> <a href="https://b.example">b</a> <a href="https://a.example">c</a></p>`,
+			want:   []span{{0, "a", 0}, {0, "b", 1}, {0, "c", 0}},
+		},
+		{
+			name:   "引用和列表中的链接",
+			cooked: `<p>开头</p><aside class="quote"><blockquote><p>看 <a href="https://a.example">这个</a></p></blockquote></aside><ul><li>第一项</li><li><a href="https://b.example">第二项</a></li></ul>`,
+			want:   []span{{2, "这个", 0}, {5, "第二项", 1}},
+		},
+		{
+			name:   "链接预览标题",
+			cooked: `<aside class="onebox" data-onebox-src="https://a.example"><article class="onebox-body"><h3><a href="https://a.example">标题</a></h3></article></aside>`,
+			want:   []span{{0, "标题", 0}},
+		},
+		{
+			name:   "站内锚点不是链接",
+			cooked: `<p><a href="#top">顶部</a> <u>下划线</u></p>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, spans := StyledLinks(Parse(tt.cooked), Options{Width: tt.width, Mark: tt.mark})
+			lines := strings.Split(text, "\n")
+			var got []span
+			for _, s := range spans {
+				got = append(got, span{s.Line, spanText(lines, s), s.Link})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("spans = %v，应为 %v\n%s", got, tt.want, text)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("spans[%d] = %v，应为 %v\n%s", i, got[i], tt.want[i], text)
+				}
+			}
+		})
+	}
+}
+
+func TestMark(t *testing.T) {
+	bold := lipgloss.NewStyle().Bold(true)
+	tests := []struct {
+		name    string
+		segs    []segment
+		keyword string
+		want    string // 高亮的部分用 [] 标出，不同的文本段用 | 分隔
+	}{
+		{
+			name:    "单个文本段",
+			segs:    []segment{{text: "foo bar foo"}},
+			keyword: "foo",
+			want:    "[foo]| bar |[foo]",
+		},
+		{
+			name:    "跨越文本段",
+			segs:    []segment{{text: "hello wo"}, {text: "rld!", style: bold}},
+			keyword: "world",
+			want:    "hello |[wo]|[rld]|!",
+		},
+		{
+			name:    "不区分大小写",
+			segs:    []segment{{text: "Go 和 GO"}},
+			keyword: "go",
+			want:    "[Go]| 和 |[GO]",
+		},
+		{
+			name:    "转小写后字节长度变化",
+			segs:    []segment{{text: "İstanbul"}},
+			keyword: "istan",
+			want:    "[İstan]|bul",
+		},
+		{
+			name:    "变长字符之后的匹配（开尔文符号）",
+			segs:    []segment{{text: "\u212a 温度"}, {text: "温度", style: bold}},
+			keyword: "度温",
+			want:    "\u212a 温|[度]|[温]|度",
+		},
+		{
+			name:    "不跨过强制换行",
+			segs:    []segment{{text: "foo"}, {newline: true}, {text: "bar"}},
+			keyword: "foobar",
+			want:    "foo||bar",
+		},
+		{
+			name:    "没有匹配",
+			segs:    []segment{{text: "abc"}},
+			keyword: "x",
+			want:    "abc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &renderer{styled: true, opts: Options{Mark: tt.keyword}}
+			var parts []string
+			for _, seg := range r.mark(tt.segs) {
+				if reflect.DeepEqual(seg.style, markStyle) {
+					parts = append(parts, "["+seg.text+"]")
+				} else {
+					parts = append(parts, seg.text)
+				}
+			}
+			if got := strings.Join(parts, "|"); got != tt.want {
+				t.Errorf("mark = %q，应为 %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Package state 保存 TUI 的阅读进度和界面状态（~/.linuxdo_state.json），下次启动时恢复
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// maxTopics 最多记录的话题阅读位置，超出时丢弃最早阅读的
+	maxTopics = 1000
+	// maxSearches 最多保留的搜索历史条数
+	maxSearches = 50
+)
+
+// State 需要跨会话保存的状态
+type State struct {
+	// Filter 上次查看的话题列表：latest、hot、new、top
+	Filter string `json:"filter,omitempty"`
+
+	// Category 上次查看的分类，为空表示按 Filter 显示
+	Category *Category `json:"category,omitempty"`
+
+	// Period 排行榜的时间范围
+	Period string `json:"period,omitempty"`
+
+	// Order、Ascending 话题列表的排序方式，Order 为空表示默认顺序
+	Order     string `json:"order,omitempty"`
+	Ascending bool   `json:"ascending,omitempty"`
+
+	// LastTopic 退出时正在阅读的话题，0 表示退出时在话题列表
+	LastTopic int `json:"last_topic,omitempty"`
+
+	// Topics 每个话题上次阅读到的楼层
+	Topics map[int]Position `json:"topics,omitempty"`
+
+	// Searches 搜索历史，最近的在前
+	Searches []string `json:"searches,omitempty"`
+}
+
+// Category 上次查看的分类
+type Category struct {
+	ID   int    `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// Position 话题的阅读位置
+type Position struct {
+	Floor  int       `json:"floor"`
+	ReadAt time.Time `json:"read_at"`
+}
+
+// Path 返回状态文件路径，可通过 LINUXDO_STATE 环境变量覆盖
+func Path() string {
+	if p := os.Getenv("LINUXDO_STATE"); p != "" {
+		return p
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".linuxdo_state.json")
+}
+
+// Load 读取状态文件，文件不存在时返回空状态
+func Load() (*State, error) {
+	s := &State{Topics: make(map[int]Position)}
+
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, err
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return &State{Topics: make(map[int]Position)}, fmt.Errorf("解析状态文件 %s 失败: %w", Path(), err)
+	}
+	if s.Topics == nil {
+		s.Topics = make(map[int]Position)
+	}
+	return s, nil
+}
+
+// Save 写入状态文件，先写临时文件再重命名，避免中途退出时损坏
+func (s *State) Save() error {
+	s.prune()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := Path()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Floor 返回话题上次阅读到的楼层，没有记录时返回 0
+func (s *State) Floor(topicID int) int {
+	return s.Topics[topicID].Floor
+}
+
+// SetFloor 记录话题的阅读位置
+func (s *State) SetFloor(topicID, floor int) {
+	if topicID == 0 || floor <= 0 {
+		return
+	}
+	s.Topics[topicID] = Position{Floor: floor, ReadAt: time.Now()}
+}
+
+// AddSearch 将关键词加入搜索历史的最前面，去掉重复项
+func (s *State) AddSearch(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return
+	}
+	searches := []string{query}
+	for _, q := range s.Searches {
+		if q != query {
+			searches = append(searches, q)
+		}
+	}
+	if len(searches) > maxSearches {
+		searches = searches[:maxSearches]
+	}
+	s.Searches = searches
+}
+
+// prune 只保留最近阅读的 maxTopics 个话题
+func (s *State) prune() {
+	if len(s.Topics) <= maxTopics {
+		return
+	}
+	ids := make([]int, 0, len(s.Topics))
+	for id := range s.Topics {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return s.Topics[ids[i]].ReadAt.After(s.Topics[ids[j]].ReadAt)
+	})
+	for _, id := range ids[maxTopics:] {
+		delete(s.Topics, id)
+	}
+}
@@ -0,0 +1,152 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func useTempState(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "state.json")
+	t.Setenv("LINUXDO_STATE", path)
+	return path
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	useTempState(t)
+	s, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if s.Topics == nil {
+		t.Fatal("Topics 为 nil")
+	}
+	// 空状态也可以直接记录阅读位置
+	s.SetFloor(1, 2)
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	path := useTempState(t)
+	if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load()
+	if err == nil {
+		t.Fatal("解析失败时应返回错误")
+	}
+	if s == nil || s.Topics == nil {
+		t.Fatal("解析失败时应返回可用的空状态")
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	useTempState(t)
+	s, _ := Load()
+	s.Filter = "hot"
+	s.Category = &Category{ID: 4, Slug: "develop", Name: "开发调优"}
+	s.Period = "monthly"
+	s.Order = "views"
+	s.Ascending = true
+	s.LastTopic = 42
+	s.SetFloor(42, 17)
+	s.AddSearch("golang")
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.Filter != "hot" || got.Period != "monthly" || got.Order != "views" || !got.Ascending || got.LastTopic != 42 {
+		t.Errorf("Load() = %+v", got)
+	}
+	if !reflect.DeepEqual(got.Category, s.Category) {
+		t.Errorf("Category = %+v, want %+v", got.Category, s.Category)
+	}
+	if got.Floor(42) != 17 {
+		t.Errorf("Floor(42) = %d, want 17", got.Floor(42))
+	}
+	if !reflect.DeepEqual(got.Searches, []string{"golang"}) {
+		t.Errorf("Searches = %v", got.Searches)
+	}
+}
+
+func TestSetFloor(t *testing.T) {
+	s := &State{Topics: make(map[int]Position)}
+	s.SetFloor(1, 5)
+	s.SetFloor(1, 3)
+	s.SetFloor(0, 9)  // 没有话题
+	s.SetFloor(2, 0)  // 无效楼层
+	s.SetFloor(3, -1) // 无效楼层
+
+	if got := s.Floor(1); got != 3 {
+		t.Errorf("Floor(1) = %d, want 3", got)
+	}
+	for _, id := range []int{0, 2, 3, 99} {
+		if got := s.Floor(id); got != 0 {
+			t.Errorf("Floor(%d) = %d, want 0", id, got)
+		}
+	}
+	if len(s.Topics) != 1 {
+		t.Errorf("Topics = %v", s.Topics)
+	}
+}
+
+func TestAddSearch(t *testing.T) {
+	s := &State{}
+	for _, q := range []string{"a", "b", "  ", "a", " c "} {
+		s.AddSearch(q)
+	}
+	if want := []string{"c", "a", "b"}; !reflect.DeepEqual(s.Searches, want) {
+		t.Errorf("Searches = %v, want %v", s.Searches, want)
+	}
+
+	for i := 0; i < maxSearches+10; i++ {
+		s.AddSearch("q" + strconv.Itoa(i))
+	}
+	if len(s.Searches) != maxSearches {
+		t.Errorf("len(Searches) = %d, want %d", len(s.Searches), maxSearches)
+	}
+	if s.Searches[0] != "q"+strconv.Itoa(maxSearches+9) {
+		t.Errorf("最近的搜索应在最前面: %v", s.Searches[:3])
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s := &State{Topics: make(map[int]Position)}
+	base := time.Now()
+	for id := 1; id <= maxTopics+5; id++ {
+		s.Topics[id] = Position{Floor: 1, ReadAt: base.Add(time.Duration(id) * time.Second)}
+	}
+	s.prune()
+
+	if len(s.Topics) != maxTopics {
+		t.Fatalf("len(Topics) = %d, want %d", len(s.Topics), maxTopics)
+	}
+	// 最早阅读的 5 个话题被丢弃
+	for id := 1; id <= 5; id++ {
+		if _, ok := s.Topics[id]; ok {
+			t.Errorf("话题 %d 应被丢弃", id)
+		}
+	}
+	if _, ok := s.Topics[maxTopics+5]; !ok {
+		t.Error("最近阅读的话题被丢弃")
+	}
+}
+
+func TestPathFromEnv(t *testing.T) {
+	path := useTempState(t)
+	if Path() != path {
+		t.Errorf("Path() = %q, want %q", Path(), path)
+	}
+	t.Setenv("LINUXDO_STATE", "")
+	if !strings.HasSuffix(Path(), ".linuxdo_state.json") {
+		t.Errorf("Path() = %q", Path())
+	}
+}
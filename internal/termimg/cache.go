@@ -0,0 +1,46 @@
+package termimg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+// Cache 图片的磁盘缓存，避免重复下载
+type Cache struct {
+	dir string
+}
+
+// NewCache 创建缓存，目录位于系统缓存目录下的 ldo/images
+func NewCache() *Cache {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return &Cache{dir: filepath.Join(base, "ldo", "images")}
+}
+
+func (c *Cache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// Load 优先从缓存读取图片，未命中时调用 fetch 下载并写入缓存
+func (c *Cache) Load(url string, fetch func(string) ([]byte, error)) ([]byte, error) {
+	path := c.path(url)
+	if data, err := os.ReadFile(path); err == nil {
+		return data, nil
+	}
+
+	data, err := fetch(url)
+	if err != nil {
+		return nil, err
+	}
+
+	// 写入缓存失败不影响显示
+	if err := os.MkdirAll(c.dir, 0700); err == nil {
+		os.WriteFile(path, data, 0600)
+	}
+	return data, nil
+}
@@ -0,0 +1,169 @@
+package termimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// resize 使用区域平均将图片缩放到 w×h 像素
+func resize(img image.Image, w, h int) *image.RGBA {
+	src := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := src.Min.Y + y*src.Dy()/h
+		y1 := max(src.Min.Y+(y+1)*src.Dy()/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := src.Min.X + x*src.Dx()/w
+			x1 := max(src.Min.X+(x+1)*src.Dx()/w, x0+1)
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a, n = r+cr, g+cg, b+cb, a+ca, n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(b / n), uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+// HalfBlockLines 用 ▀ 字符渲染图片，每个字符单元显示上下两个像素
+func HalfBlockLines(img image.Image, cols, rows int) []string {
+	pixels := resize(img, cols, rows*2)
+	lines := make([]string, rows)
+	for row := 0; row < rows; row++ {
+		var s strings.Builder
+		for x := 0; x < cols; x++ {
+			top := hexColor(pixels.RGBAAt(x, row*2))
+			bottom := hexColor(pixels.RGBAAt(x, row*2+1))
+			s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(top)).Background(lipgloss.Color(bottom)).Render("▀"))
+		}
+		lines[row] = s.String()
+	}
+	return lines
+}
+
+// hexColor 将像素转为十六进制颜色，透明部分按黑色背景混合
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// kitty 使用 Kitty 图形协议传输 PNG，终端负责缩放到 cols×rows 个字符单元
+func kitty(img image.Image, cols, rows int) (string, error) {
+	data, err := encodePNG(img)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	// 数据需要分块发送，每块不超过 4096 字节
+	const chunkSize = 4096
+	var s strings.Builder
+	for i := 0; i < len(encoded); i += chunkSize {
+		end := min(i+chunkSize, len(encoded))
+		more := 0
+		if end < len(encoded) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&s, "\x1b_Ga=T,f=100,q=2,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, encoded[i:end])
+		} else {
+			fmt.Fprintf(&s, "\x1b_Gm=%d;%s\x1b\\", more, encoded[i:end])
+		}
+	}
+	return s.String(), nil
+}
+
+// iterm 使用 iTerm2 内联图片协议（WezTerm 等终端也支持）
+func iterm(img image.Image, cols, rows int) (string, error) {
+	data, err := encodePNG(img)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		len(data), cols, rows, base64.StdEncoding.EncodeToString(data)), nil
+}
+
+// sixel 将图片缩放到 w×h 像素，量化为 256 色后按 Sixel 格式编码
+func sixel(img image.Image, w, h int) string {
+	scaled := resize(img, w, h)
+	paletted := image.NewPaletted(scaled.Bounds(), palette.Plan9)
+	draw.FloydSteinberg.Draw(paletted, scaled.Bounds(), scaled, image.Point{})
+
+	var s strings.Builder
+	s.WriteString("\x1bPq")
+	fmt.Fprintf(&s, "\"1;1;%d;%d", w, h)
+	for i, c := range paletted.Palette {
+		r, g, b, _ := c.RGBA()
+		fmt.Fprintf(&s, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, b*100/0xffff)
+	}
+
+	// 每 6 行像素为一个 sixel 带，按颜色逐层输出
+	for band := 0; band < h; band += 6 {
+		used := make(map[uint8]bool)
+		for y := band; y < min(band+6, h); y++ {
+			for x := 0; x < w; x++ {
+				used[paletted.ColorIndexAt(x, y)] = true
+			}
+		}
+
+		first := true
+		for idx := range used {
+			if !first {
+				s.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&s, "#%d", idx)
+
+			var run byte
+			count := 0
+			flush := func() {
+				switch {
+				case count > 3:
+					fmt.Fprintf(&s, "!%d%c", count, run)
+				case count > 0:
+					s.WriteString(strings.Repeat(string(run), count))
+				}
+			}
+			for x := 0; x < w; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && band+dy < h; dy++ {
+					if paletted.ColorIndexAt(x, band+dy) == idx {
+						bits |= 1 << dy
+					}
+				}
+				ch := '?' + bits
+				if ch == run {
+					count++
+					continue
+				}
+				flush()
+				run, count = ch, 1
+			}
+			flush()
+		}
+		s.WriteByte('-')
+	}
+
+	s.WriteString("\x1b\\")
+	return s.String()
+}
@@ -0,0 +1,127 @@
+// Package termimg 在终端中显示图片，支持 Kitty、iTerm2、Sixel 图形协议和半块字符回退方案
+package termimg
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+)
+
+// Protocol 终端图形协议
+type Protocol int
+
+const (
+	HalfBlocks Protocol = iota // 使用 ▀ 字符和前景/背景色拼出图片，任何支持颜色的终端都可用
+	Kitty
+	ITerm
+	Sixel
+)
+
+// 终端字符单元的大致像素尺寸，用于 Sixel 和行数估算
+const (
+	cellWidth  = 10
+	cellHeight = 20
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case Kitty:
+		return "kitty"
+	case ITerm:
+		return "iterm"
+	case Sixel:
+		return "sixel"
+	}
+	return "halfblocks"
+}
+
+// ParseProtocol 解析配置中的协议名，auto 或空字符串时根据环境变量自动检测
+func ParseProtocol(name string) (Protocol, error) {
+	switch strings.ToLower(name) {
+	case "", "auto":
+		return Detect(), nil
+	case "kitty":
+		return Kitty, nil
+	case "iterm", "iterm2":
+		return ITerm, nil
+	case "sixel":
+		return Sixel, nil
+	case "halfblocks", "half-blocks", "blocks":
+		return HalfBlocks, nil
+	}
+	return HalfBlocks, fmt.Errorf("未知的图片协议: %s（可选 auto, kitty, iterm, sixel, halfblocks）", name)
+}
+
+// Detect 根据环境变量猜测终端支持的图形协议
+// Sixel 无法可靠地通过环境变量检测，需要在配置中显式指定
+func Detect() Protocol {
+	term := os.Getenv("TERM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", strings.Contains(term, "kitty"), os.Getenv("TERM_PROGRAM") == "ghostty":
+		return Kitty
+	case os.Getenv("TERM_PROGRAM") == "iTerm.app", os.Getenv("TERM_PROGRAM") == "WezTerm", os.Getenv("LC_TERMINAL") == "iTerm2":
+		return ITerm
+	}
+	return HalfBlocks
+}
+
+// Decode 解码图片数据，支持 PNG、JPEG、GIF（取第一帧）
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("无法解码图片: %w", err)
+	}
+	return img, nil
+}
+
+// Fit 计算图片在不超过 maxCols 列、maxRows 行时占用的列数和行数，保持宽高比
+// 终端字符单元高约为宽的两倍
+func Fit(img image.Image, maxCols, maxRows int) (int, int) {
+	b := img.Bounds()
+	if b.Dx() == 0 || b.Dy() == 0 || maxCols <= 0 || maxRows <= 0 {
+		return 0, 0
+	}
+
+	cols := min(maxCols, max(b.Dx()/cellWidth, 1))
+	rows := (cols*b.Dy()*cellWidth/b.Dx() + cellHeight - 1) / cellHeight
+	if rows > maxRows {
+		rows = maxRows
+		cols = max(rows*cellHeight*b.Dx()/(b.Dy()*cellWidth), 1)
+	}
+	return cols, max(rows, 1)
+}
+
+// Render 按协议编码图片，返回输出内容和占用的终端行数
+func Render(img image.Image, p Protocol, maxCols, maxRows int) (string, int, error) {
+	cols, rows := Fit(img, maxCols, maxRows)
+	if cols == 0 {
+		return "", 0, fmt.Errorf("图片尺寸无效")
+	}
+
+	switch p {
+	case Kitty:
+		out, err := kitty(img, cols, rows)
+		return out, rows, err
+	case ITerm:
+		out, err := iterm(img, cols, rows)
+		return out, rows, err
+	case Sixel:
+		return sixel(img, cols*cellWidth, rows*cellHeight), rows, nil
+	}
+
+	lines := HalfBlockLines(img, cols, rows)
+	return strings.Join(lines, "\n"), len(lines), nil
+}
+
+// Clear 返回清除已显示图片的控制序列，Kitty 的图片不会随清屏消失
+func Clear(p Protocol) string {
+	if p == Kitty {
+		return "\x1b_Ga=d,q=2\x1b\\"
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package termimg
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ViewerImage 查看器中的一张图片，Load 在显示时才调用
+type ViewerImage struct {
+	Title string
+	Load  func() (image.Image, error)
+}
+
+// Viewer 全屏逐张显示图片
+// 实现了 tea.ExecCommand 接口，TUI 可以通过 tea.Exec 暂时交出终端；CLI 直接调用 Run
+type Viewer struct {
+	Images   []ViewerImage
+	Protocol Protocol
+	Help     string // 底部的按键提示
+
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+func (v *Viewer) SetStdin(r io.Reader)  { v.stdin = r }
+func (v *Viewer) SetStdout(w io.Writer) { v.stdout = w }
+func (v *Viewer) SetStderr(io.Writer)   {}
+
+// Run 显示图片并等待按键：n/空格/→ 下一张，p/← 上一张，q/Esc 退出
+func (v *Viewer) Run() error {
+	if len(v.Images) == 0 {
+		return nil
+	}
+	in, out := v.stdin, v.stdout
+	if in == nil {
+		in = os.Stdin
+	}
+	if out == nil {
+		out = os.Stdout
+	}
+
+	cols, rows := 80, 24
+	if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		cols, rows = w, h
+	}
+
+	// 原始模式下逐键读取，换行需要显式回车
+	newline := "\n"
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err == nil {
+			defer term.Restore(int(f.Fd()), state)
+			newline = "\r\n"
+		}
+	}
+
+	help := v.Help
+	if help == "" {
+		help = "n/空格: 下一张 | p: 上一张 | q: 退出"
+	}
+
+	clear := func() {
+		fmt.Fprint(out, Clear(v.Protocol)+"\x1b[2J\x1b[H")
+	}
+	defer clear()
+
+	for i := 0; i >= 0 && i < len(v.Images); {
+		clear()
+		img := v.Images[i]
+		fmt.Fprintf(out, "[%d/%d] %s%s%s", i+1, len(v.Images), img.Title, newline, newline)
+
+		if decoded, err := img.Load(); err != nil {
+			fmt.Fprintf(out, "❌ %v%s", err, newline)
+		} else if output, _, err := Render(decoded, v.Protocol, cols, rows-4); err != nil {
+			fmt.Fprintf(out, "❌ %v%s", err, newline)
+		} else {
+			fmt.Fprint(out, strings.ReplaceAll(output, "\n", newline)+newline)
+		}
+
+		fmt.Fprint(out, newline+help)
+
+		key := make([]byte, 8)
+		n, err := in.Read(key)
+		if err != nil {
+			return nil
+		}
+		switch strings.TrimRight(string(key[:n]), "\r\n") {
+		case "q", "Q", "\x1b", "\x03":
+			return nil
+		case "p", "h", "k", "\x1b[D", "\x1b[A":
+			if i > 0 {
+				i--
+			}
+		default:
+			i++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+// Package theme 定义 TUI 的配色方案，支持内置主题、终端背景检测、NO_COLOR 和用户自定义配色
+package theme
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/config"
+)
+
+// Theme 一套配色方案
+type Theme struct {
+	Name string
+
+	Primary    lipgloss.TerminalColor // 标题栏背景、帖子作者、光标
+	OnPrimary  lipgloss.TerminalColor // 标题栏文字
+	Selected   lipgloss.TerminalColor // 选中行背景
+	OnSelected lipgloss.TerminalColor // 选中行文字
+	Muted      lipgloss.TerminalColor // 帮助信息、次要文字
+	Warning    lipgloss.TerminalColor // 加载中、确认提示
+	Highlight  lipgloss.TerminalColor // 搜索结果中的关键词
+	Mark       lipgloss.TerminalColor // 话题内搜索匹配的背景
+	OnMark     lipgloss.TerminalColor // 话题内搜索匹配的文字
+	Link       lipgloss.TerminalColor
+	Mention    lipgloss.TerminalColor
+	Code       lipgloss.TerminalColor // 行内代码和未高亮的代码块
+	CodeBg     lipgloss.TerminalColor // 行内代码背景
+	Spoiler    lipgloss.TerminalColor // 剧透内容的前景和背景
+
+	// CodeStyle 未配置 ui.code_style 时使用的语法高亮方案
+	CodeStyle string
+
+	// Monochrome 不使用任何颜色，选中和匹配改用反色显示
+	Monochrome bool
+}
+
+// Dark 深色终端的默认主题
+var Dark = Theme{
+	Name:       "dark",
+	Primary:    lipgloss.Color("#7D56F4"),
+	OnPrimary:  lipgloss.Color("#FAFAFA"),
+	Selected:   lipgloss.Color("#7D56F4"),
+	OnSelected: lipgloss.Color("#FFFFFF"),
+	Muted:      lipgloss.Color("#888888"),
+	Warning:    lipgloss.Color("#FFA500"),
+	Highlight:  lipgloss.Color("#FFFF00"),
+	Mark:       lipgloss.Color("#FFFF00"),
+	OnMark:     lipgloss.Color("#000000"),
+	Link:       lipgloss.Color("#61AFEF"),
+	Mention:    lipgloss.Color("#56B6C2"),
+	Code:       lipgloss.Color("#E5C07B"),
+	CodeBg:     lipgloss.Color("#333333"),
+	Spoiler:    lipgloss.Color("#444444"),
+	CodeStyle:  "monokai",
+}
+
+// Light 浅色终端的主题
+var Light = Theme{
+	Name:       "light",
+	Primary:    lipgloss.Color("#5A3FC0"),
+	OnPrimary:  lipgloss.Color("#FFFFFF"),
+	Selected:   lipgloss.Color("#DDD5FF"),
+	OnSelected: lipgloss.Color("#1A1A1A"),
+	Muted:      lipgloss.Color("#6B6B6B"),
+	Warning:    lipgloss.Color("#B35C00"),
+	Highlight:  lipgloss.Color("#C7254E"),
+	Mark:       lipgloss.Color("#FFE066"),
+	OnMark:     lipgloss.Color("#000000"),
+	Link:       lipgloss.Color("#0550AE"),
+	Mention:    lipgloss.Color("#0A7B83"),
+	Code:       lipgloss.Color("#953800"),
+	CodeBg:     lipgloss.Color("#EEEEEE"),
+	Spoiler:    lipgloss.Color("#BBBBBB"),
+	CodeStyle:  "github",
+}
+
+// Mono 单色主题，设置了 NO_COLOR 环境变量时自动使用
+var Mono = Theme{
+	Name:       "mono",
+	Primary:    lipgloss.NoColor{},
+	OnPrimary:  lipgloss.NoColor{},
+	Selected:   lipgloss.NoColor{},
+	OnSelected: lipgloss.NoColor{},
+	Muted:      lipgloss.NoColor{},
+	Warning:    lipgloss.NoColor{},
+	Highlight:  lipgloss.NoColor{},
+	Mark:       lipgloss.NoColor{},
+	OnMark:     lipgloss.NoColor{},
+	Link:       lipgloss.NoColor{},
+	Mention:    lipgloss.NoColor{},
+	Code:       lipgloss.NoColor{},
+	CodeBg:     lipgloss.NoColor{},
+	Spoiler:    lipgloss.NoColor{},
+	Monochrome: true,
+}
+
+var builtin = map[string]Theme{
+	"dark":  Dark,
+	"light": Light,
+	"mono":  Mono,
+}
+
+// Detect 根据环境选择主题：NO_COLOR 时使用单色，否则按终端背景色选择深色或浅色
+func Detect() Theme {
+	if os.Getenv("NO_COLOR") != "" {
+		return Mono
+	}
+	if lipgloss.HasDarkBackground() {
+		return Dark
+	}
+	return Light
+}
+
+// Names 返回内置主题和自定义主题的名称，内置主题在前
+func Names(custom map[string]config.ThemeConfig) []string {
+	names := []string{"dark", "light", "mono"}
+	var extra []string
+	for name := range custom {
+		if _, ok := builtin[name]; !ok {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+// Load 根据配置选择主题，ui.theme 为空或 auto 时自动检测
+// 出错时返回自动检测的主题和错误
+func Load(cfg config.UIConfig) (Theme, error) {
+	name := strings.ToLower(cfg.Theme)
+	if name == "" || name == "auto" {
+		return Detect(), nil
+	}
+	t, err := Get(name, cfg.Themes)
+	if err != nil {
+		return Detect(), err
+	}
+	return t, nil
+}
+
+// Get 按名称获取主题，自定义主题优先，可以覆盖同名的内置主题
+func Get(name string, custom map[string]config.ThemeConfig) (Theme, error) {
+	if c, ok := custom[name]; ok {
+		return fromConfig(name, c)
+	}
+	if t, ok := builtin[name]; ok {
+		return t, nil
+	}
+	return Theme{}, fmt.Errorf("未知的主题: %s（可选 %s）", name, strings.Join(Names(custom), ", "))
+}
+
+// fromConfig 在基础主题上应用自定义颜色
+func fromConfig(name string, c config.ThemeConfig) (Theme, error) {
+	baseName := c.Base
+	if baseName == "" {
+		baseName = "dark"
+	}
+	base, ok := builtin[baseName]
+	if !ok {
+		return Theme{}, fmt.Errorf("主题 %s 的 base 只能是 dark、light 或 mono", name)
+	}
+	t := base
+	t.Name = name
+
+	colors := []struct {
+		value  string
+		target *lipgloss.TerminalColor
+	}{
+		{c.Primary, &t.Primary},
+		{c.OnPrimary, &t.OnPrimary},
+		{c.Selected, &t.Selected},
+		{c.OnSelected, &t.OnSelected},
+		{c.Muted, &t.Muted},
+		{c.Warning, &t.Warning},
+		{c.Highlight, &t.Highlight},
+		{c.Mark, &t.Mark},
+		{c.OnMark, &t.OnMark},
+		{c.Link, &t.Link},
+		{c.Mention, &t.Mention},
+		{c.Code, &t.Code},
+		{c.CodeBg, &t.CodeBg},
+		{c.Spoiler, &t.Spoiler},
+	}
+	for _, color := range colors {
+		if color.value != "" {
+			*color.target = lipgloss.Color(color.value)
+		}
+	}
+	if c.CodeStyle != "" {
+		t.CodeStyle = c.CodeStyle
+	}
+	return t, nil
+}
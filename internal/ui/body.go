@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/render"
+)
+
+// bodyKey 帖子正文渲染结果依赖的参数，任一变化都需要重新渲染
+type bodyKey struct {
+	width     int
+	theme     string
+	mark      string
+	highlight bool
+	codeStyle string
+	images    int // 显示图片时为图片的加载进度，帖子不含图片或不显示图片时为 -1
+}
+
+type renderedBody struct {
+	key    bodyKey
+	cooked string
+	text   string
+	links  []render.LinkSpan
+}
+
+// searchText 帖子正文中可以搜索的文字，已转为小写
+type searchText struct {
+	cooked string
+	lower  string
+}
+
+// bodyStore 缓存帖子正文的渲染结果和搜索用的文字，移动光标、点击帖子时只需重新渲染作者行，
+// 话题内搜索每次按键也不必重新解析所有帖子；Model 按值复制时共享同一份。
+// 只保留最近一次渲染用到的帖子，不会随阅读的话题增多而增长
+type bodyStore struct {
+	bodies map[int]renderedBody // 按帖子 ID
+	texts  map[int]searchText   // 按帖子 ID
+}
+
+func newBodyStore() *bodyStore {
+	return &bodyStore{
+		bodies: make(map[int]renderedBody),
+		texts:  make(map[int]searchText),
+	}
+}
+
+// text 返回帖子中可以搜索的文字（小写），内容没有变化时使用缓存；只能在 Update 和 View 中调用
+func (b *bodyStore) text(post client.Post) string {
+	if cached, ok := b.texts[post.ID]; ok && cached.cooked == post.Cooked {
+		return cached.lower
+	}
+	lower := postText(post)
+	b.texts[post.ID] = searchText{cooked: post.Cooked, lower: lower}
+	return lower
+}
+
+// postText 返回帖子正文中显示的文字（小写），不含图片的占位文字和链接地址
+func postText(post client.Post) string {
+	return strings.ToLower(render.Parse(post.Cooked).Text())
+}
+
+// bodyRender 一次渲染过程，记录用到的帖子
+type bodyRender struct {
+	store *bodyStore
+	used  map[int]renderedBody
+}
+
+func (b *bodyStore) begin() *bodyRender {
+	return &bodyRender{store: b, used: make(map[int]renderedBody)}
+}
+
+// end 丢弃这次渲染没有用到的帖子
+func (b *bodyStore) end(r *bodyRender) {
+	b.bodies = r.used
+	for id := range b.texts {
+		if _, ok := r.used[id]; !ok {
+			delete(b.texts, id)
+		}
+	}
+}
+
+// body 返回帖子正文的渲染结果和其中链接的位置，参数和内容都没有变化时使用缓存
+func (r *bodyRender) body(post client.Post, opts render.Options, imageVersion int) (string, []render.LinkSpan) {
+	key := bodyKey{
+		width:     opts.Width,
+		theme:     activeTheme.Name,
+		mark:      opts.Mark,
+		highlight: opts.Highlight,
+		codeStyle: opts.CodeStyle,
+		images:    -1,
+	}
+	if opts.Image != nil && strings.Contains(post.Cooked, "<img") {
+		key.images = imageVersion
+	}
+
+	if cached, ok := r.store.bodies[post.ID]; ok && cached.key == key && cached.cooked == post.Cooked {
+		r.used[post.ID] = cached
+		return cached.text, cached.links
+	}
+	body := renderedBody{key: key, cooked: post.Cooked}
+	body.text, body.links = render.StyledLinks(render.Parse(post.Cooked), opts)
+	r.used[post.ID] = body
+	return body.text, body.links
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"io"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/render"
+	"github.com/muesli/termenv"
+)
+
+type bookmarkToggledMsg struct {
+	postID     int
+	bookmarkID int // 0 表示已取消收藏
+	err        error
+}
+
+type copiedMsg struct {
+	what string
+	err  error
+}
+
+// osc52Msg 系统剪贴板不可用，改为通过 OSC 52 交给终端复制
+type osc52Msg struct {
+	what string
+	text string
+}
+
+// refreshTopicDetail 重新渲染话题内容，并记录每个帖子在视口中的起始行和链接的位置
+func (m *Model) refreshTopicDetail() {
+	content, offsets, links := m.renderTopicDetail()
+	m.postOffsets = offsets
+	m.postLinks = links
+	m.viewport.SetContent(content)
+}
+
+// scrollToCurrentPost 滚动视口，使光标所在的帖子可见
+// 帖子能完整显示时尽量完整显示，否则让帖子开头对齐视口顶部
+func (m *Model) scrollToCurrentPost() {
+	if m.currentPostIdx >= len(m.postOffsets) {
+		return
+	}
+	start := m.postOffsets[m.currentPostIdx]
+	end := m.viewport.TotalLineCount()
+	if m.currentPostIdx+1 < len(m.postOffsets) {
+		end = m.postOffsets[m.currentPostIdx+1]
+	}
+
+	top, height := m.viewport.YOffset, m.viewport.Height
+	switch {
+	case start < top, start >= top+height, end-start > height:
+		m.viewport.SetYOffset(start)
+	case end > top+height:
+		m.viewport.SetYOffset(end - height)
+	}
+}
+
+// moveCursor 将光标移动 delta 个帖子，移过最后一个已加载的帖子时自动加载更多
+func (m Model) moveCursor(delta int) (tea.Model, tea.Cmd) {
+	idx := m.currentPostIdx + delta
+	if idx >= len(m.posts) {
+		if len(m.posts) < len(m.allPostIDs) && !m.loading {
+			m.loading = true
+			m.notice = "加载更多回复..."
+			return m, m.loadMorePosts()
+		}
+		idx = len(m.posts) - 1
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	if idx == m.currentPostIdx {
+		return m, nil
+	}
+
+	m.currentPostIdx = idx
+	m.refreshTopicDetail()
+	m.scrollToCurrentPost()
+	return m, nil
+}
+
+// replyToCurrent 返回回复光标所在帖子时使用的楼层号，首楼视为回复话题
+func (m Model) replyToCurrent() int {
+	if len(m.posts) > m.currentPostIdx && m.posts[m.currentPostIdx].PostNumber > 1 {
+		return m.posts[m.currentPostIdx].PostNumber
+	}
+	return 0
+}
+
+func (m Model) toggleBookmark(post client.Post) tea.Cmd {
+	return func() tea.Msg {
+		if post.Bookmarked && post.BookmarkID > 0 {
+			err := m.client.DeleteBookmark(post.BookmarkID)
+			return bookmarkToggledMsg{postID: post.ID, bookmarkID: 0, err: err}
+		}
+		id, err := m.client.CreateBookmark(post.ID)
+		return bookmarkToggledMsg{postID: post.ID, bookmarkID: id, err: err}
+	}
+}
+
+// copyPost 复制帖子的 Markdown 内容或链接
+// 优先使用系统剪贴板，不可用时（如 SSH 会话）通过 OSC 52 交给终端处理
+func (m Model) copyPost(post client.Post, link bool) tea.Cmd {
+	what := "帖子内容"
+	text := post.Raw
+	if link {
+		what = "帖子链接"
+		text = m.client.TopicURL(m.topicDetail.ID, post.PostNumber)
+	} else if text == "" {
+		text = render.Markdown(render.Parse(post.Cooked))
+	}
+	text = strings.TrimSpace(text)
+
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(text); err != nil {
+			return osc52Msg{what: what, text: text}
+		}
+		return copiedMsg{what: what}
+	}
+}
+
+// osc52Copy 通过 OSC 52 把文字交给终端复制。在 tea.Exec 中运行，写入期间界面暂停刷新，
+// 转义序列不会和界面的输出交错
+type osc52Copy struct {
+	text string
+	out  io.Writer
+}
+
+func (c *osc52Copy) Run() error {
+	termenv.NewOutput(c.out).Copy(c.text)
+	return nil
+}
+
+func (c *osc52Copy) SetStdin(io.Reader)    {}
+func (c *osc52Copy) SetStdout(w io.Writer) { c.out = w }
+func (c *osc52Copy) SetStderr(io.Writer)   {}
+
+// copyOSC52 在程序的输出中写入 OSC 52 序列
+func copyOSC52(msg osc52Msg) tea.Cmd {
+	return tea.Exec(&osc52Copy{text: msg.text}, func(err error) tea.Msg {
+		return copiedMsg{what: msg.what, err: err}
+	})
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/client"
+)
+
+// draftSaveInterval 回复编辑器自动保存草稿的间隔
+const draftSaveInterval = 10 * time.Second
+
+type draftLoadedMsg struct {
+	topicID int
+	draft   *client.Draft
+	err     error
+}
+
+type draftTickMsg struct {
+	gen int
+}
+
+type draftSavedMsg struct {
+	content  string
+	sequence int
+	err      error
+}
+
+type draftDeletedMsg struct {
+	err error
+}
+
+func (m Model) loadDraft(topicID int) tea.Cmd {
+	return func() tea.Msg {
+		draft, err := m.client.GetDraft(client.TopicDraftKey(topicID))
+		return draftLoadedMsg{topicID: topicID, draft: draft, err: err}
+	}
+}
+
+func draftTick(gen int) tea.Cmd {
+	return tea.Tick(draftSaveInterval, func(time.Time) tea.Msg {
+		return draftTickMsg{gen: gen}
+	})
+}
+
+// saveDraftIfChanged 内容有变化时保存草稿
+func (m Model) saveDraftIfChanged() tea.Cmd {
+	if m.topicDetail == nil {
+		return nil
+	}
+	return m.saveDraftContent(m.composer.Value())
+}
+
+// saveDraftContent 将指定内容保存为当前话题的草稿
+func (m Model) saveDraftContent(content string) tea.Cmd {
+	if m.topicDetail == nil {
+		return nil
+	}
+	content = strings.TrimSpace(content)
+	if content == "" || content == m.draftSaved {
+		return nil
+	}
+
+	topicID := m.topicDetail.ID
+	replyTo := m.replyToPost
+	sequence := m.draftSequence
+	return func() tea.Msg {
+		seq, err := m.client.SaveDraft(client.TopicDraftKey(topicID), content, replyTo, sequence)
+		return draftSavedMsg{content: content, sequence: seq, err: err}
+	}
+}
+
+func (m Model) deleteDraft(topicID int) tea.Cmd {
+	sequence := m.draftSequence
+	return func() tea.Msg {
+		err := m.client.DeleteDraft(client.TopicDraftKey(topicID), sequence)
+		return draftDeletedMsg{err: err}
+	}
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/editor"
+	"github.com/lhpqaq/ldo/internal/render"
+)
+
+type editorFinishedMsg struct {
+	content string
+	err     error
+}
+
+// editorInitialMsg 异步获取到草稿后再打开编辑器
+type editorInitialMsg struct {
+	content string
+}
+
+// openEditor 挂起 TUI，在 $EDITOR 中编辑 initial，退出后返回 editorFinishedMsg
+func openEditor(initial string) tea.Cmd {
+	path, err := editor.TempFile(initial)
+	if err != nil {
+		return func() tea.Msg { return editorFinishedMsg{err: err} }
+	}
+	return tea.ExecProcess(editor.Command(path), func(err error) tea.Msg {
+		content, readErr := editor.ReadFile(path)
+		if err == nil {
+			err = readErr
+		}
+		return editorFinishedMsg{content: content, err: err}
+	})
+}
+
+// editWithDraft 先获取服务器草稿作为预填内容，再打开编辑器
+func (m Model) editWithDraft(topicID int) tea.Cmd {
+	return func() tea.Msg {
+		draft, err := m.client.GetDraft(client.TopicDraftKey(topicID))
+		if err != nil || draft == nil {
+			return editorInitialMsg{}
+		}
+		return editorInitialMsg{content: draft.Reply}
+	}
+}
+
+// quoteText 生成当前帖子的引用，作为编辑器的预填内容
+func (m Model) quoteText(post client.Post) string {
+	text := post.Raw
+	if text == "" {
+		text = render.Markdown(render.Parse(post.Cooked))
+	}
+	return client.QuoteMarkdown(post.Username, post.PostNumber, m.topicDetail.ID, text)
+}
+
+func (m Model) updateEditorConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "y", msg.String() == "Y", key.Matches(msg, keys.Enter):
+		m.state = topicDetailView
+		return m, m.createPost(m.topicDetail.ID, m.editorContent, m.replyToPost)
+	case msg.String() == "e":
+		// 继续在编辑器中修改
+		return m, openEditor(m.editorContent)
+	case msg.String() == "c":
+		// 转到内置编辑器继续编辑
+		m.state = composerView
+		m.composer.SetValue(m.editorContent)
+		m.composer.Focus()
+		return m, nil
+	case msg.String() == "n", msg.String() == "N", key.Matches(msg, keys.Back):
+		// 放弃发送，内容保存为草稿
+		m.state = topicDetailView
+		m.notice = "📝 已取消，内容已保存为草稿"
+		return m, m.saveDraftContent(m.editorContent)
+	}
+	return m, nil
+}
+
+func (m Model) renderEditorConfirm() string {
+	var s strings.Builder
+
+	title := " 👀 预览回复 "
+	if m.replyToPost > 0 {
+		title = fmt.Sprintf(" 👀 预览回复 #%d楼 ", m.replyToPost)
+	}
+	s.WriteString(titleStyle.Render(title) + "\n\n")
+
+	// 预览区域保留底部提示行
+	lines := strings.Split(render.Styled(render.ParseMarkdown(m.editorContent), m.renderOptions(m.width-4)), "\n")
+	maxLines := m.height - 6
+	if maxLines < 5 {
+		maxLines = 5
+	}
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines-1], helpStyle.Render(fmt.Sprintf("... (还有 %d 行)", len(lines)-maxLines+1)))
+	}
+	s.WriteString(strings.Join(lines, "\n") + "\n\n")
+
+	s.WriteString(helpStyle.Render("y/Enter: 发送 | e: 继续编辑 | c: 转到内置编辑器 | n/Esc: 取消并保存草稿"))
+	return s.String()
+}
@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/client"
+)
+
+// findBatchLimit 每次向后搜索最多加载的批次数（每批 20 帖），避免长话题一次加载过多
+const findBatchLimit = 10
+
+type findMoreMsg struct {
+	topicID int
+	query   string
+	posts   []client.Post
+	found   bool
+	err     error
+}
+
+// startFind 进入话题内搜索输入
+func (m Model) startFind() (tea.Model, tea.Cmd) {
+	m.state = findInputView
+	m.findInput.Reset()
+	m.findInput.Focus()
+	return m, textarea.Blink
+}
+
+func (m Model) updateFindInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.state = topicDetailView
+		m.findInput.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		m.findInput.Blur()
+		return m.find(strings.TrimSpace(m.findInput.Value()))
+	default:
+		var cmd tea.Cmd
+		m.findInput, cmd = m.findInput.Update(msg)
+		return m, cmd
+	}
+}
+
+// find 在当前话题中搜索关键词，从光标所在帖子开始，关键词为空时清除搜索
+func (m Model) find(query string) (tea.Model, tea.Cmd) {
+	m.state = topicDetailView
+	m.findQuery = query
+	m.refreshTopicDetail()
+	if m.findQuery == "" {
+		m.notice = ""
+		return m, nil
+	}
+	if len(m.posts) > m.currentPostIdx && m.postMatches(m.posts[m.currentPostIdx], m.findQuery) {
+		m.notice = m.findStatus()
+		return m, nil
+	}
+	return m.findNext(1)
+}
+
+// postMatches 判断帖子正文中显示的文字是否包含关键词（不区分大小写），使用缓存的文字
+func (m Model) postMatches(post client.Post, query string) bool {
+	return strings.Contains(m.bodies.text(post), strings.ToLower(query))
+}
+
+// findNext 将光标移到下一个（dir=1）或上一个（dir=-1）匹配的帖子
+// 向后搜索到已加载帖子末尾时继续加载后面的帖子，全部加载完后从头开始
+func (m Model) findNext(dir int) (tea.Model, tea.Cmd) {
+	if m.findQuery == "" || len(m.posts) == 0 {
+		return m, nil
+	}
+
+	for i := m.currentPostIdx + dir; i >= 0 && i < len(m.posts); i += dir {
+		if m.postMatches(m.posts[i], m.findQuery) {
+			return m.moveToMatch(i, ""), nil
+		}
+	}
+
+	if dir > 0 && m.nextUnloadedIdx() < len(m.allPostIDs) {
+		if m.loading {
+			return m, nil
+		}
+		m.loading = true
+		m.notice = fmt.Sprintf("🔍 正在后续帖子中搜索 \"%s\"...", m.findQuery)
+		return m, m.findInMorePosts(m.findQuery)
+	}
+
+	// 回绕到另一端继续搜索
+	start, wrapped := 0, "已到末尾，从头开始"
+	if dir < 0 {
+		start, wrapped = len(m.posts)-1, "已到开头，从末尾开始"
+	}
+	for i := start; i >= 0 && i < len(m.posts); i += dir {
+		if m.postMatches(m.posts[i], m.findQuery) {
+			return m.moveToMatch(i, wrapped), nil
+		}
+	}
+
+	m.notice = fmt.Sprintf("未找到 \"%s\"", m.findQuery)
+	return m, nil
+}
+
+func (m Model) moveToMatch(idx int, note string) Model {
+	m.currentPostIdx = idx
+	m.refreshTopicDetail()
+	m.scrollToCurrentPost()
+	m.notice = m.findStatus()
+	if note != "" {
+		m.notice += "  " + note
+	}
+	return m
+}
+
+// findStatus 返回当前匹配在已加载帖子中的位置
+func (m Model) findStatus() string {
+	total, current := 0, 0
+	for i, post := range m.posts {
+		if m.postMatches(post, m.findQuery) {
+			total++
+			if i == m.currentPostIdx {
+				current = total
+			}
+		}
+	}
+	return fmt.Sprintf("🔍 \"%s\" %d/%d（%s，%s: 清除）", m.findQuery, current, total,
+		helpPair(keys.FindNext, keys.FindPrev, "下一个/上一个"), keys.Back.Help().Key)
+}
+
+// nextUnloadedIdx 返回最后一个已加载帖子之后的帖子在 allPostIDs 中的位置
+func (m Model) nextUnloadedIdx() int {
+	if len(m.posts) == 0 {
+		return 0
+	}
+	last := m.posts[len(m.posts)-1].ID
+	for i, id := range m.allPostIDs {
+		if id == last {
+			return i + 1
+		}
+	}
+	return len(m.allPostIDs)
+}
+
+// findInMorePosts 逐批加载后面的帖子，直到找到匹配或达到批次上限；已预取的帖子不再请求
+func (m Model) findInMorePosts(query string) tea.Cmd {
+	start := m.nextUnloadedIdx()
+	topicID := m.topicDetail.ID
+	stop := min(start+findBatchLimit*postBatchSize, len(m.allPostIDs))
+	// 预取结果只能在 Update 中读取，先取出整个搜索范围内可用的帖子
+	have, _ := m.collectPosts(m.allPostIDs[start:stop])
+	return func() tea.Msg {
+		var loaded []client.Post
+		for start < stop {
+			end := min(start+postBatchSize, stop)
+			ids := m.allPostIDs[start:end]
+			var missing []int
+			for _, id := range ids {
+				if _, ok := have[id]; !ok {
+					missing = append(missing, id)
+				}
+			}
+			var fetched []client.Post
+			if len(missing) > 0 {
+				var err error
+				fetched, err = m.client.GetPostsByIDs(topicID, missing)
+				if err != nil {
+					return findMoreMsg{topicID: topicID, query: query, posts: loaded, err: err}
+				}
+			}
+			posts := orderPosts(ids, have, fetched)
+			loaded = append(loaded, posts...)
+			start = end
+			for _, post := range posts {
+				// 在 Cmd 中运行，不能访问 Model 中共享的缓存
+				if strings.Contains(postText(post), strings.ToLower(query)) {
+					return findMoreMsg{topicID: topicID, query: query, posts: loaded, found: true}
+				}
+			}
+		}
+		return findMoreMsg{topicID: topicID, query: query, posts: loaded}
+	}
+}
+
+func (m Model) handleFindMore(msg findMoreMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	// 搜索期间已切换到其他话题
+	if m.topicDetail == nil || m.topicDetail.ID != msg.topicID {
+		return m, nil
+	}
+	m.err = msg.err
+	if len(msg.posts) == 0 && msg.err != nil {
+		m.notice = ""
+		return m, nil
+	}
+
+	// 搜索期间加载更多的帖子已经在 m.posts 中；跳转楼层后已加载的帖子不再与结果相连时丢弃结果
+	posts := m.newPosts(msg.posts)
+	if len(posts) > 0 && slices.Index(m.allPostIDs, posts[0].ID) < m.nextUnloadedIdx() {
+		posts = nil
+	}
+	m.posts = append(m.posts, posts...)
+	m.prefetch.consume(posts)
+	m.refreshTopicDetail()
+	images := m.loadPostImages()
+	if msg.query != m.findQuery {
+		return m, images
+	}
+
+	if msg.found {
+		for _, post := range msg.posts {
+			if !m.postMatches(post, m.findQuery) {
+				continue
+			}
+			for i := range m.posts {
+				if m.posts[i].ID == post.ID {
+					return m.moveToMatch(i, ""), images
+				}
+			}
+			break
+		}
+	}
+
+	if m.nextUnloadedIdx() < len(m.allPostIDs) {
+		m.notice = fmt.Sprintf("🔍 已加载 %d 帖仍未找到 \"%s\"，按 %s 继续搜索", len(msg.posts), m.findQuery, keys.FindNext.Help().Key)
+		return m, images
+	}
+	// 已全部加载，回绕搜索
+	next, cmd := m.findNext(1)
+	return next, tea.Batch(cmd, images)
+}
+
+// clearFind 清除话题内搜索和高亮
+func (m *Model) clearFind() {
+	m.findQuery = ""
+	m.notice = ""
+	m.refreshTopicDetail()
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/client"
+)
+
+// 举报流程的步骤
+const (
+	flagStepChoose = iota
+	flagStepMessage
+	flagStepConfirm
+)
+
+type postFlaggedMsg struct {
+	err error
+}
+
+func (m Model) updateFlag(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.flagStep {
+	case flagStepChoose:
+		switch {
+		case key.Matches(msg, keys.Back):
+			m.state = topicDetailView
+		case key.Matches(msg, keys.Up):
+			if m.flagIdx > 0 {
+				m.flagIdx--
+			}
+		case key.Matches(msg, keys.Down):
+			if m.flagIdx < len(client.FlagTypes)-1 {
+				m.flagIdx++
+			}
+		case key.Matches(msg, keys.Enter):
+			if client.FlagTypes[m.flagIdx].NeedMessage {
+				m.flagStep = flagStepMessage
+				m.flagInput.Reset()
+				m.flagInput.Focus()
+				return m, textarea.Blink
+			}
+			m.flagStep = flagStepConfirm
+		}
+	case flagStepMessage:
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.flagStep = flagStepChoose
+		case tea.KeyCtrlD:
+			if strings.TrimSpace(m.flagInput.Value()) != "" {
+				m.flagStep = flagStepConfirm
+			}
+		default:
+			var cmd tea.Cmd
+			m.flagInput, cmd = m.flagInput.Update(msg)
+			return m, cmd
+		}
+	case flagStepConfirm:
+		switch {
+		case msg.String() == "y", msg.String() == "Y":
+			m.state = topicDetailView
+			if len(m.posts) > m.currentPostIdx {
+				flagType := client.FlagTypes[m.flagIdx]
+				message := ""
+				if flagType.NeedMessage {
+					message = strings.TrimSpace(m.flagInput.Value())
+				}
+				return m, m.flagPost(m.posts[m.currentPostIdx].ID, flagType, message)
+			}
+		case msg.String() == "n", msg.String() == "N", key.Matches(msg, keys.Back):
+			m.state = topicDetailView
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderFlag() string {
+	var s strings.Builder
+
+	post := m.posts[m.currentPostIdx]
+	s.WriteString(titleStyle.Render(fmt.Sprintf(" 🚩 举报 #%d楼 @%s ", post.PostNumber, post.Username)) + "\n\n")
+
+	switch m.flagStep {
+	case flagStepChoose:
+		s.WriteString("选择举报原因:\n\n")
+		for i, t := range client.FlagTypes {
+			line := fmt.Sprintf("  %s", t.Label)
+			if i == m.flagIdx {
+				s.WriteString(selectedStyle.Render("▶"+line[1:]) + "\n")
+			} else {
+				s.WriteString(line + "\n")
+			}
+		}
+		s.WriteString("\n" + helpStyle.Render(helpLine(
+			helpPair(keys.Up, keys.Down, "选择"),
+			helpAs(keys.Enter, "下一步"),
+			helpAs(keys.Back, "取消"),
+		)))
+	case flagStepMessage:
+		s.WriteString("请说明举报原因（将发送给版主）:\n\n")
+		s.WriteString(m.flagInput.View() + "\n\n")
+		s.WriteString(helpStyle.Render("Ctrl+D: 下一步 | Esc: 返回"))
+	case flagStepConfirm:
+		flagType := client.FlagTypes[m.flagIdx]
+		s.WriteString(fmt.Sprintf("举报原因: %s\n", flagType.Label))
+		if flagType.NeedMessage {
+			s.WriteString(fmt.Sprintf("说明: %s\n", strings.TrimSpace(m.flagInput.Value())))
+		}
+		s.WriteString("\n" + loadingStyle.Render("确认举报该帖子? (y/n)"))
+	}
+
+	return s.String()
+}
+
+func (m Model) flagPost(postID int, flagType client.FlagType, message string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.client.FlagPost(postID, flagType, message)
+		return postFlaggedMsg{err: err}
+	}
+}
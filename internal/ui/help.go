@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpDesc 帮助页中对部分操作的完整说明，底部帮助栏空间有限只能显示简称
+var helpDesc = map[string]string{
+	"quit":        "退出程序",
+	"back":        "返回上一页 / 清除话题内搜索",
+	"help":        "显示本帮助",
+	"palette":     "打开命令面板",
+	"enter":       "打开选中的话题",
+	"open":        "在浏览器中打开",
+	"load_more":   "加载更多",
+	"top":         "跳到开头",
+	"last":        "跳到末尾",
+	"filter":      "切换列表：最新 / 热门 / 新话题 / 排行",
+	"refresh":     "刷新列表",
+	"search":      "全站搜索",
+	"period":      "排行榜时间范围：今日 / 本周 / 本月 / 本季度 / 今年 / 全部",
+	"sort":        "排序：默认 / 最新活动 / 浏览数 / 点赞数 / 帖子数 / 创建时间",
+	"reverse":     "切换升序 / 降序",
+	"editor":      "在外部编辑器中回复",
+	"quote":       "引用当前帖子回复",
+	"react":       "表情回应",
+	"copy":        "复制帖子内容",
+	"copy_link":   "复制帖子链接",
+	"links":       "列出帖子中的链接",
+	"images":      "开关图片预览",
+	"view_images": "全屏查看图片",
+	"poll":        "参与投票",
+	"flag":        "举报帖子",
+	"profile":     "查看作者资料",
+	"watch":       "切换话题通知级别",
+	"find":        "在话题内搜索",
+	"jump":        "跳转到指定楼层",
+	"scroll_up":   "向上滚动",
+	"scroll_down": "向下滚动",
+}
+
+// pickerDesc 链接、表情、投票、举报选择中各操作的说明
+var pickerDesc = map[string]string{
+	"quit":     "退出程序",
+	"back":     "返回",
+	"up":       "上移",
+	"down":     "下移",
+	"left":     "表情：左移",
+	"right":    "表情：右移",
+	"enter":    "确认",
+	"new_tab":  "链接：站内话题在新标签页中打开",
+	"open":     "链接：在浏览器中打开",
+	"next_tab": "投票：切换到下一个投票",
+	"toggle":   "投票：勾选多选项",
+	"unvote":   "投票：撤回投票",
+}
+
+// helpSection 帮助页中的一组按键说明
+type helpSection struct {
+	title string
+	rows  [][2]string // 按键, 说明
+}
+
+// openHelp 打开全屏快捷键帮助，当前界面的按键排在最前面
+func (m Model) openHelp() Model {
+	m.prevState = m.state
+	m.state = helpView
+	m.helpViewport.SetContent(m.helpContent())
+	m.helpViewport.GotoTop()
+	return m
+}
+
+func (m Model) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Back), key.Matches(msg, keys.Quit), key.Matches(msg, keys.Help):
+		m.state = m.prevState
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.helpViewport, cmd = m.helpViewport.Update(msg)
+	return m, cmd
+}
+
+// helpSections 按界面分组列出所有按键，按键来自当前生效的快捷键配置
+func (m Model) helpSections() []helpSection {
+	list := helpSection{title: "话题列表 / 搜索结果 / 用户资料"}
+	detail := helpSection{title: "话题详情"}
+	find := helpSection{title: "话题内搜索（" + keys.Find.Help().Key + " 输入关键词后）"}
+	pickers := helpSection{title: "链接 / 表情 / 投票 / 举报选择"}
+
+	for _, a := range keys.actions() {
+		if !a.binding.Enabled() {
+			continue
+		}
+		labels := make([]string, 0, len(a.binding.Keys()))
+		for _, k := range a.binding.Keys() {
+			labels = append(labels, keyLabel(k))
+		}
+		desc := a.help
+		if d, ok := helpDesc[a.name]; ok {
+			desc = d
+		}
+		row := [2]string{strings.Join(labels, " "), desc}
+		if a.scope&scopeList != 0 {
+			list.rows = append(list.rows, row)
+		}
+		if a.scope&scopeDetail != 0 {
+			detail.rows = append(detail.rows, row)
+		}
+		if a.scope&scopeFind != 0 {
+			find.rows = append(find.rows, row)
+		}
+		if a.scope&scopePicker != 0 {
+			pickers.rows = append(pickers.rows, [2]string{row[0], pickerDesc[a.name]})
+		}
+	}
+	pickers.rows = append(pickers.rows, [2]string{"1-9", "链接：直接打开对应链接"})
+
+	composer := helpSection{title: "回复编辑器", rows: [][2]string{
+		{"Ctrl+D", "发送"},
+		{"Ctrl+R", "切换 Markdown 预览"},
+		{"Ctrl+O", "上传附件"},
+		{"Ctrl+X", "在外部编辑器中继续编辑"},
+		{"Esc", "取消"},
+	}}
+	palette := helpSection{title: "命令面板（" + keys.Palette.Help().Key + "）", rows: [][2]string{
+		{"输入", "模糊匹配命令，如 hot、theme、cat 分类名"},
+		{"open <ID>", "打开话题，直接输入数字也可以"},
+		{"tab <ID>", "在新标签页中打开话题"},
+		{"jump <楼层>", "跳转到楼层"},
+		{"search <关键词>", "全站搜索"},
+		{"find <关键词>", "话题内搜索"},
+		{"Tab", "补全选中的命令"},
+		{"Enter", "执行"},
+	}}
+
+	mouse := helpSection{title: "鼠标", rows: [][2]string{
+		{"滚轮", "列表中移动选中项，话题和帮助中滚动"},
+		{"单击", "话题列表 / 搜索结果：选中；话题中：移动光标到该帖子"},
+		{"双击", "打开话题"},
+		{"单击链接", "打开帖子中的链接"},
+		{"单击标签", "切换过滤器（latest/hot/new/top）或标签页"},
+	}}
+
+	if m.prevState == topicDetailView {
+		return []helpSection{detail, find, composer, pickers, list, palette, mouse}
+	}
+	return []helpSection{list, detail, find, composer, pickers, palette, mouse}
+}
+
+func (m Model) helpContent() string {
+	sections := m.helpSections()
+
+	keyWidth := 0
+	for _, sec := range sections {
+		for _, row := range sec.rows {
+			keyWidth = max(keyWidth, lipgloss.Width(row[0]))
+		}
+	}
+
+	var s strings.Builder
+	for i, sec := range sections {
+		if i > 0 {
+			s.WriteString("\n")
+		}
+		s.WriteString(authorStyle.Render(sec.title) + "\n")
+		for _, row := range sec.rows {
+			pad := strings.Repeat(" ", keyWidth-lipgloss.Width(row[0]))
+			s.WriteString(fmt.Sprintf("  %s%s  %s\n", row[0], pad, helpStyle.Render(row[1])))
+		}
+	}
+	return s.String()
+}
+
+func (m Model) renderHelp() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(" ❓ 快捷键帮助 ") + "\n\n")
+	s.WriteString(m.helpViewport.View() + "\n")
+	var closeKeys []string
+	for _, b := range []key.Binding{keys.Back, keys.Quit, keys.Help} {
+		if b.Enabled() {
+			closeKeys = append(closeKeys, b.Help().Key)
+		}
+	}
+	s.WriteString(helpStyle.Render(helpLine(
+		helpPair(keys.Up, keys.Down, "滚动"),
+		fmt.Sprintf("%3.0f%%", m.helpViewport.ScrollPercent()*100),
+		strings.Join(closeKeys, "/")+": 关闭",
+	)))
+	return s.String()
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/render"
+	"github.com/lhpqaq/ldo/internal/termimg"
+)
+
+// 帖子内图片预览的最大尺寸（字符单元）
+const (
+	inlineImageCols = 60
+	inlineImageRows = 20
+)
+
+// imageStore 已下载的图片，Model 按值复制时共享同一份
+type imageStore struct {
+	cache   *termimg.Cache
+	images  map[string]image.Image
+	errs    map[string]error
+	pending map[string]bool
+	lines   map[string][]string // 半块字符渲染结果，键为 url@列数
+	version int                 // 图片开始加载或加载完成时递增，含图片的帖子需要重新渲染
+}
+
+func newImageStore() *imageStore {
+	return &imageStore{
+		cache:   termimg.NewCache(),
+		images:  make(map[string]image.Image),
+		errs:    make(map[string]error),
+		pending: make(map[string]bool),
+		lines:   make(map[string][]string),
+	}
+}
+
+// load 从磁盘缓存或服务器获取图片并解码
+func (s *imageStore) load(c *client.Client, url string) (image.Image, error) {
+	data, err := s.cache.Load(c.ResolveURL(url), c.DownloadImage)
+	if err != nil {
+		return nil, err
+	}
+	return termimg.Decode(data)
+}
+
+type imageLoadedMsg struct {
+	url string
+	img image.Image
+	err error
+}
+
+type imageViewerClosedMsg struct {
+	err error
+}
+
+func (m Model) loadImage(url string) tea.Cmd {
+	return func() tea.Msg {
+		img, err := m.images.load(m.client, url)
+		return imageLoadedMsg{url: url, img: img, err: err}
+	}
+}
+
+// loadPostImages 下载已加载帖子中尚未请求过的图片，仅在开启图片预览时生效
+func (m Model) loadPostImages() tea.Cmd {
+	if !m.showImages {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	for _, post := range m.posts {
+		for _, img := range render.Parse(post.Cooked).Images() {
+			if m.images.pending[img.URL] || m.images.images[img.URL] != nil || m.images.errs[img.URL] != nil {
+				continue
+			}
+			m.images.pending[img.URL] = true
+			m.images.version++
+			cmds = append(cmds, m.loadImage(img.URL))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// inlineImage 在图片占位文本下方显示半块字符预览
+func (m Model) inlineImage(img render.Inline, width int) []string {
+	if err := m.images.errs[img.URL]; err != nil {
+		return []string{helpStyle.Render(fmt.Sprintf("  ❌ %v", err))}
+	}
+	decoded := m.images.images[img.URL]
+	if decoded == nil {
+		if m.images.pending[img.URL] {
+			return []string{loadingStyle.Render("  ⏳ 图片加载中...")}
+		}
+		return nil
+	}
+
+	maxCols := min(width, inlineImageCols)
+	key := fmt.Sprintf("%s@%d", img.URL, maxCols)
+	if lines, ok := m.images.lines[key]; ok {
+		return lines
+	}
+	cols, rows := termimg.Fit(decoded, maxCols, inlineImageRows)
+	lines := termimg.HalfBlockLines(decoded, cols, rows)
+	m.images.lines[key] = lines
+	return lines
+}
+
+// viewPostImages 暂时交出终端，用图形协议全屏查看当前帖子的图片
+func (m Model) viewPostImages(post client.Post) tea.Cmd {
+	images := render.Parse(post.Cooked).Images()
+	if len(images) == 0 {
+		return nil
+	}
+
+	protocol, err := termimg.ParseProtocol(m.config.UI.ImageProtocol)
+	if err != nil {
+		return func() tea.Msg { return imageViewerClosedMsg{err: err} }
+	}
+
+	viewer := &termimg.Viewer{Protocol: protocol}
+	for _, img := range images {
+		url := img.URL
+		title := img.Text
+		if title == "" {
+			title = url
+		}
+		viewer.Images = append(viewer.Images, termimg.ViewerImage{
+			Title: title,
+			Load: func() (image.Image, error) {
+				if decoded := m.images.images[url]; decoded != nil {
+					return decoded, nil
+				}
+				return m.images.load(m.client, url)
+			},
+		})
+	}
+
+	return tea.Exec(viewer, func(err error) tea.Msg {
+		return imageViewerClosedMsg{err: err}
+	})
+}
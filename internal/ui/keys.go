@@ -0,0 +1,394 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/lhpqaq/ldo/internal/config"
+)
+
+type keyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	Enter    key.Binding
+	Back     key.Binding
+	Quit     key.Binding
+	Filter   key.Binding
+	Reply    key.Binding
+	Like     key.Binding
+	Refresh  key.Binding
+	Open     key.Binding
+	LoadMore key.Binding
+	Jump     key.Binding
+	Top      key.Binding
+	Last     key.Binding
+	Search   key.Binding
+	Watch    key.Binding
+	Profile  key.Binding
+	React    key.Binding
+	Poll     key.Binding
+	Flag     key.Binding
+	Editor   key.Binding
+	Quote    key.Binding
+	Images   key.Binding
+	View     key.Binding
+	Links    key.Binding
+	PrevPost key.Binding
+	NextPost key.Binding
+	Bookmark key.Binding
+	Copy     key.Binding
+	CopyLink key.Binding
+	Find     key.Binding
+	FindNext key.Binding
+	FindPrev key.Binding
+	Help     key.Binding
+	Palette  key.Binding
+	NewTab   key.Binding
+	CloseTab key.Binding
+	NextTab  key.Binding
+	PrevTab  key.Binding
+	Layout   key.Binding
+	Period   key.Binding
+	Sort     key.Binding
+	Reverse  key.Binding
+	Left     key.Binding
+	Right    key.Binding
+	Toggle   key.Binding
+	Unvote   key.Binding
+
+	ScrollUp     key.Binding
+	ScrollDown   key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+}
+
+// keys 当前生效的快捷键，启动时由 LoadKeyMap 根据配置设置
+var keys = newKeyMap(keyPresets["default"])
+
+// keyScope 快捷键生效的界面，同一界面内的按键不能重复
+type keyScope int
+
+const (
+	scopeList   keyScope = 1 << iota // 话题列表、搜索结果、用户资料
+	scopeDetail                      // 话题详情
+	scopeFind                        // 话题内搜索激活时的 n/N，优先于详情页的其他按键
+	scopePicker                      // 链接、表情、投票、举报选择和命令面板
+)
+
+// keyAction 可配置的快捷键操作
+type keyAction struct {
+	name    string
+	help    string
+	binding *key.Binding
+	scope   keyScope
+}
+
+// actions 返回所有可配置的操作，配置文件中使用 name 指定
+func (k *keyMap) actions() []keyAction {
+	return []keyAction{
+		{"quit", "退出", &k.Quit, scopeList | scopeDetail | scopePicker},
+		{"back", "返回", &k.Back, scopeList | scopeDetail | scopePicker},
+		{"help", "帮助", &k.Help, scopeList | scopeDetail},
+		{"palette", "命令", &k.Palette, scopeList | scopeDetail},
+		{"up", "上移", &k.Up, scopeList | scopePicker},
+		{"down", "下移", &k.Down, scopeList | scopePicker},
+		{"left", "左移", &k.Left, scopePicker},
+		{"right", "右移", &k.Right, scopePicker},
+		{"enter", "打开", &k.Enter, scopeList | scopePicker},
+		{"new_tab", "新标签页打开", &k.NewTab, scopeList | scopePicker},
+		{"next_tab", "下一标签页", &k.NextTab, scopeList | scopeDetail | scopePicker},
+		{"prev_tab", "上一标签页", &k.PrevTab, scopeList | scopeDetail},
+		{"close_tab", "关闭标签页", &k.CloseTab, scopeDetail},
+		{"open", "浏览器", &k.Open, scopeList | scopeDetail | scopePicker},
+		{"toggle", "勾选", &k.Toggle, scopePicker},
+		{"unvote", "撤回投票", &k.Unvote, scopePicker},
+		{"load_more", "更多", &k.LoadMore, scopeList | scopeDetail},
+		{"top", "开头", &k.Top, scopeList | scopeDetail},
+		{"last", "末尾", &k.Last, scopeList | scopeDetail},
+		{"filter", "切换", &k.Filter, scopeList},
+		{"refresh", "刷新", &k.Refresh, scopeList},
+		{"search", "搜索", &k.Search, scopeList},
+		{"layout", "分栏", &k.Layout, scopeList},
+		{"period", "时间范围", &k.Period, scopeList},
+		{"sort", "排序", &k.Sort, scopeList},
+		{"reverse", "升降序", &k.Reverse, scopeList},
+		{"scroll_up", "上滚", &k.ScrollUp, scopeDetail},
+		{"scroll_down", "下滚", &k.ScrollDown, scopeDetail},
+		{"page_up", "上翻页", &k.PageUp, scopeDetail},
+		{"page_down", "下翻页", &k.PageDown, scopeDetail},
+		{"half_page_up", "上翻半页", &k.HalfPageUp, scopeDetail},
+		{"half_page_down", "下翻半页", &k.HalfPageDown, scopeDetail},
+		{"prev_post", "上一帖", &k.PrevPost, scopeDetail},
+		{"next_post", "下一帖", &k.NextPost, scopeDetail},
+		{"reply", "回复", &k.Reply, scopeDetail},
+		{"editor", "编辑器回复", &k.Editor, scopeDetail},
+		{"quote", "引用", &k.Quote, scopeDetail},
+		{"like", "点赞", &k.Like, scopeDetail},
+		{"react", "表情", &k.React, scopeDetail},
+		{"bookmark", "收藏", &k.Bookmark, scopeDetail},
+		{"copy", "复制内容", &k.Copy, scopeDetail},
+		{"copy_link", "复制链接", &k.CopyLink, scopeDetail},
+		{"links", "链接", &k.Links, scopeDetail},
+		{"images", "图片", &k.Images, scopeDetail},
+		{"view_images", "看图", &k.View, scopeDetail},
+		{"poll", "投票", &k.Poll, scopeDetail},
+		{"flag", "举报", &k.Flag, scopeDetail},
+		{"profile", "作者", &k.Profile, scopeDetail},
+		{"watch", "通知", &k.Watch, scopeDetail},
+		{"find", "搜索", &k.Find, scopeDetail},
+		{"jump", "跳转", &k.Jump, scopeDetail},
+		{"find_next", "下一个匹配", &k.FindNext, scopeFind},
+		{"find_prev", "上一个匹配", &k.FindPrev, scopeFind},
+	}
+}
+
+// keyPresets 预设的快捷键方案，vim 和 emacs 只列出与 default 不同的操作
+var keyPresets = map[string]map[string][]string{
+	"default": {
+		"quit":           {"q", "ctrl+c"},
+		"back":           {"esc"},
+		"help":           {"?"},
+		"palette":        {":"},
+		"up":             {"up", "k"},
+		"down":           {"down", "j"},
+		"left":           {"left", "h"},
+		"right":          {"right", "l"},
+		"enter":          {"enter"},
+		"new_tab":        {"t"},
+		"next_tab":       {"tab"},
+		"prev_tab":       {"shift+tab"},
+		"close_tab":      {"x"},
+		"open":           {"o"},
+		"toggle":         {" "},
+		"unvote":         {"x"},
+		"load_more":      {"n"},
+		"top":            {"home"},
+		"last":           {"G", "end"},
+		"filter":         {"f"},
+		"refresh":        {"g"},
+		"search":         {"s"},
+		"layout":         {"|"},
+		"period":         {"p"},
+		"sort":           {"S"},
+		"reverse":        {"r"},
+		"scroll_up":      {"up"},
+		"scroll_down":    {"down"},
+		"page_up":        {"pgup"},
+		"page_down":      {"pgdown", " ", "f"},
+		"half_page_up":   {"ctrl+u"},
+		"half_page_down": {"ctrl+d", "d"},
+		"prev_post":      {"k", "["},
+		"next_post":      {"j", "]"},
+		"reply":          {"r"},
+		"editor":         {"E"},
+		"quote":          {"Q"},
+		"like":           {"l"},
+		"react":          {"e"},
+		"bookmark":       {"b"},
+		"copy":           {"y"},
+		"copy_link":      {"Y"},
+		"links":          {"L"},
+		"images":         {"i"},
+		"view_images":    {"v"},
+		"poll":           {"p"},
+		"flag":           {"!"},
+		"profile":        {"u"},
+		"watch":          {"w"},
+		"find":           {"/"},
+		"jump":           {"#"},
+		"find_next":      {"n"},
+		"find_prev":      {"N"},
+	},
+	"vim": {
+		"top":       {"g", "home"},
+		"refresh":   {"R"},
+		"page_up":   {"ctrl+b", "pgup"},
+		"page_down": {"ctrl+f", "pgdown", " "},
+	},
+	"emacs": {
+		"quit":      {"ctrl+c", "q"},
+		"back":      {"ctrl+g", "esc"},
+		"palette":   {"alt+x", ":"},
+		"up":        {"ctrl+p", "up"},
+		"down":      {"ctrl+n", "down"},
+		"left":      {"ctrl+b", "left"},
+		"right":     {"ctrl+f", "right"},
+		"page_up":   {"alt+v", "pgup"},
+		"page_down": {"ctrl+v", "pgdown", " "},
+		"top":       {"alt+<", "home"},
+		"last":      {"alt+>", "end"},
+		"prev_post": {"ctrl+p", "["},
+		"next_post": {"ctrl+n", "]"},
+		"find":      {"ctrl+s", "/"},
+		"find_next": {"ctrl+s", "n"},
+		"find_prev": {"ctrl+r", "N"},
+		"jump":      {"alt+g", "#"},
+	},
+}
+
+// newKeyMap 根据 操作 -> 按键 的映射创建快捷键，未列出的操作使用 default 方案
+func newKeyMap(bindings map[string][]string) keyMap {
+	var k keyMap
+	for _, a := range k.actions() {
+		keyList, ok := bindings[a.name]
+		if !ok {
+			keyList = keyPresets["default"][a.name]
+		}
+		*a.binding = key.NewBinding(key.WithKeys(keyList...))
+		if len(keyList) == 0 {
+			// 配置为空列表表示禁用该操作
+			a.binding.SetEnabled(false)
+		} else {
+			a.binding.SetHelp(keyLabel(keyList[0]), a.help)
+		}
+	}
+	return k
+}
+
+// viewportKeyMap 使用当前快捷键配置中的翻页按键滚动 viewport，up/down 为逐行滚动的按键
+func (k *keyMap) viewportKeyMap(up, down key.Binding) viewport.KeyMap {
+	return viewport.KeyMap{
+		Up:           up,
+		Down:         down,
+		PageUp:       k.PageUp,
+		PageDown:     k.PageDown,
+		HalfPageUp:   k.HalfPageUp,
+		HalfPageDown: k.HalfPageDown,
+	}
+}
+
+// LoadKeyMap 根据配置加载快捷键：先选择预设方案（ui.keymap），再应用 ui.keys 中的自定义按键
+// 配置有误（未知方案、未知操作或按键冲突）时返回错误，此时使用能正确加载的部分
+func LoadKeyMap(cfg config.UIConfig) error {
+	presetName := cfg.Keymap
+	if presetName == "" {
+		presetName = "default"
+	}
+	preset, ok := keyPresets[presetName]
+	if !ok {
+		keys = newKeyMap(keyPresets["default"])
+		return fmt.Errorf("未知的快捷键方案: %s（可选 default, vim, emacs）", presetName)
+	}
+	keys = newKeyMap(preset)
+	if len(cfg.Keys) == 0 {
+		return nil
+	}
+
+	bindings := make(map[string][]string, len(preset)+len(cfg.Keys))
+	for name, keyList := range preset {
+		bindings[name] = keyList
+	}
+	valid := make(map[string]bool)
+	for _, a := range keys.actions() {
+		valid[a.name] = true
+	}
+	var unknown []string
+	for name, keyList := range cfg.Keys {
+		if !valid[name] {
+			unknown = append(unknown, name)
+			continue
+		}
+		bindings[name] = keyList
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("未知的快捷键操作: %s", strings.Join(unknown, ", "))
+	}
+
+	custom := newKeyMap(bindings)
+	if err := custom.checkConflicts(); err != nil {
+		return err
+	}
+	keys = custom
+	return nil
+}
+
+// checkConflicts 检查同一界面内是否有按键绑定了多个操作
+func (k *keyMap) checkConflicts() error {
+	var conflicts []string
+	for _, scope := range []keyScope{scopeList, scopeDetail, scopeFind, scopePicker} {
+		owner := make(map[string]string)
+		for _, a := range k.actions() {
+			if a.scope&scope == 0 {
+				continue
+			}
+			for _, keyName := range a.binding.Keys() {
+				if prev, ok := owner[keyName]; ok && prev != a.name {
+					conflicts = append(conflicts, fmt.Sprintf("%s 同时绑定了 %s 和 %s", keyName, prev, a.name))
+					continue
+				}
+				owner[keyName] = a.name
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("按键冲突: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// keyLabel 将按键名转换为帮助信息中显示的形式
+func keyLabel(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case " ":
+		return "Space"
+	}
+	parts := strings.Split(k, "+")
+	for i, p := range parts {
+		if len(p) > 1 {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		} else if i > 0 && strings.HasPrefix(k, "ctrl+") {
+			// Ctrl 组合键不区分大小写，按习惯显示为大写
+			parts[i] = strings.ToUpper(p)
+		}
+	}
+	return strings.Join(parts, "+")
+}
+
+// helpKey 返回单个快捷键的帮助，操作被禁用时返回空字符串
+func helpKey(b key.Binding) string {
+	if !b.Enabled() {
+		return ""
+	}
+	return b.Help().Key + ": " + b.Help().Desc
+}
+
+// helpAs 返回快捷键的帮助，使用 desc 代替默认说明，操作被禁用时返回空字符串
+func helpAs(b key.Binding, desc string) string {
+	if !b.Enabled() {
+		return ""
+	}
+	return b.Help().Key + ": " + desc
+}
+
+// helpPair 将一对相反的操作合并为一条帮助，如 "j/k: 上/下一帖"
+func helpPair(a, b key.Binding, desc string) string {
+	if !a.Enabled() || !b.Enabled() {
+		return helpKey(a) + helpKey(b)
+	}
+	return a.Help().Key + "/" + b.Help().Key + ": " + desc
+}
+
+// helpLine 拼接帮助信息，忽略被禁用的操作
+func helpLine(entries ...string) string {
+	var parts []string
+	for _, e := range entries {
+		if e != "" {
+			parts = append(parts, e)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
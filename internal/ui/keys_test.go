@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/config"
+	"github.com/lhpqaq/ldo/internal/render"
+)
+
+// useKeyMap 加载快捷键配置，测试结束后恢复默认方案
+func useKeyMap(t *testing.T, cfg config.UIConfig) error {
+	t.Helper()
+	t.Cleanup(func() { keys = newKeyMap(keyPresets["default"]) })
+	return LoadKeyMap(cfg)
+}
+
+func TestKeyPresetsHaveNoConflicts(t *testing.T) {
+	for name := range keyPresets {
+		t.Run(name, func(t *testing.T) {
+			if err := useKeyMap(t, config.UIConfig{Keymap: name}); err != nil {
+				t.Fatalf("LoadKeyMap: %v", err)
+			}
+			if err := keys.checkConflicts(); err != nil {
+				t.Fatal(err)
+			}
+			for _, a := range keys.actions() {
+				if !a.binding.Enabled() {
+					t.Errorf("%s 没有绑定按键", a.name)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadKeyMapErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.UIConfig
+		want string
+	}{
+		{"未知方案", config.UIConfig{Keymap: "nano"}, "未知的快捷键方案"},
+		{"未知操作", config.UIConfig{Keys: map[string][]string{"fly": {"F"}}}, "fly"},
+		{"列表中冲突", config.UIConfig{Keys: map[string][]string{"filter": {"j"}}}, "j 同时绑定了"},
+		{"选择界面中冲突", config.UIConfig{Keys: map[string][]string{"unvote": {"o"}}}, "o 同时绑定了"},
+		{"预设与自定义冲突", config.UIConfig{Keymap: "emacs", Keys: map[string][]string{"left": {"ctrl+n"}}}, "ctrl+n 同时绑定了"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := useKeyMap(t, tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v，应包含 %q", err, tt.want)
+			}
+			// 配置有误时仍然使用没有冲突的按键
+			if err := keys.checkConflicts(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestPickerKeysFollowKeymap(t *testing.T) {
+	if err := useKeyMap(t, config.UIConfig{Keymap: "emacs"}); err != nil {
+		t.Fatal(err)
+	}
+	m := Model{
+		state: linkPickerView,
+		links: []render.LinkRef{{Text: "a", URL: "https://a.example"}, {Text: "b", URL: "https://b.example"}},
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m = next.(Model)
+	if m.linkIdx != 1 {
+		t.Fatalf("Ctrl+N 后 linkIdx = %d，应为 1", m.linkIdx)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	m = next.(Model)
+	if m.linkIdx != 0 {
+		t.Fatalf("Ctrl+P 后 linkIdx = %d，应为 0", m.linkIdx)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if state := next.(Model).state; state != topicDetailView {
+		t.Fatalf("Ctrl+G 后 state = %v，应返回话题详情", state)
+	}
+}
+
+func TestCtrlCAlwaysQuits(t *testing.T) {
+	if err := useKeyMap(t, config.UIConfig{Keys: map[string][]string{"quit": {}}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range []viewState{topicListView, topicDetailView, composerView, linkPickerView, helpView, paletteView} {
+		_, cmd := Model{state: state}.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+		if cmd == nil {
+			t.Fatalf("state %v: Ctrl+C 没有退出", state)
+		}
+		if _, ok := cmd().(tea.QuitMsg); !ok {
+			t.Fatalf("state %v: Ctrl+C 没有退出", state)
+		}
+	}
+}
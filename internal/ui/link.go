@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/browser"
+	"github.com/lhpqaq/ldo/internal/render"
+)
+
+// openLinkPicker 列出当前帖子中的链接
+func (m Model) openLinkPicker() (tea.Model, tea.Cmd) {
+	if len(m.posts) <= m.currentPostIdx {
+		return m, nil
+	}
+	doc := render.Parse(m.posts[m.currentPostIdx].Cooked)
+	m.links = doc.Links()
+	if len(m.links) == 0 {
+		m.notice = "当前帖子没有链接"
+		return m, nil
+	}
+	m.state = linkPickerView
+	m.linkIdx = 0
+	return m, nil
+}
+
+func (m Model) updateLinkPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Back):
+		m.state = topicDetailView
+	case key.Matches(msg, keys.Up):
+		if m.linkIdx > 0 {
+			m.linkIdx--
+		}
+	case key.Matches(msg, keys.Down):
+		if m.linkIdx < len(m.links)-1 {
+			m.linkIdx++
+		}
+	case key.Matches(msg, keys.Enter):
+		return m.followLink(m.linkIdx)
+	case key.Matches(msg, keys.NewTab):
+		// 站内话题在新标签页中打开
+		if m.linkIdx < len(m.links) {
+			if topicID, floor, ok := m.client.TopicLink(m.links[m.linkIdx].URL); ok {
+				m.state = topicDetailView
+				m.pendingFloor = floor
+				return m.openInNewTab(topicID)
+			}
+			return m.followLink(m.linkIdx)
+		}
+	case key.Matches(msg, keys.Open):
+		// 站内链接也在浏览器中打开
+		if m.linkIdx < len(m.links) {
+			m.state = topicDetailView
+			return m, openInBrowser(m.client.ResolveURL(m.links[m.linkIdx].URL))
+		}
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	default:
+		// 数字键直接选择前 9 个链接
+		if n, err := strconv.Atoi(msg.String()); err == nil && n >= 1 && n <= len(m.links) {
+			return m.followLink(n - 1)
+		}
+	}
+	return m, nil
+}
+
+// followLink 打开选中的链接：站内话题在 ldo 中打开，其他链接交给浏览器
+func (m Model) followLink(idx int) (tea.Model, tea.Cmd) {
+	if idx >= len(m.links) {
+		return m, nil
+	}
+	link := m.links[idx]
+	m.state = topicDetailView
+
+	topicID, floor, ok := m.client.TopicLink(link.URL)
+	if !ok {
+		return m, openInBrowser(m.client.ResolveURL(link.URL))
+	}
+
+	m.err = nil
+	if m.topicDetail != nil && m.topicDetail.ID == topicID {
+		if floor > 0 {
+			return m, m.jumpToFloor(floor)
+		}
+		return m, nil
+	}
+	m.pendingFloor = floor
+	m.searchResults = nil
+	return m, m.fetchTopicDetail(topicID)
+}
+
+func (m Model) renderLinkPicker() string {
+	var s strings.Builder
+
+	post := m.posts[m.currentPostIdx]
+	s.WriteString(titleStyle.Render(fmt.Sprintf(" 🔗 #%d楼 @%s 的链接 ", post.PostNumber, post.Username)) + "\n\n")
+
+	maxVisible := max(m.height-6, 5)
+	start := 0
+	if m.linkIdx >= maxVisible {
+		start = m.linkIdx - maxVisible + 1
+	}
+	end := min(start+maxVisible, len(m.links))
+
+	for i := start; i < end; i++ {
+		link := m.links[i]
+		tag := ""
+		if _, _, ok := m.client.TopicLink(link.URL); ok {
+			tag = "[站内] "
+		}
+		line := fmt.Sprintf("%3d. %s%s", i+1, tag, truncate(link.Text, max(m.width/2, 20)))
+		url := ""
+		if link.Text != link.URL {
+			url = "  " + truncate(link.URL, max(m.width/2-8, 20))
+		}
+		if i == m.linkIdx {
+			s.WriteString(selectedStyle.Render("▶"+line[1:]+url) + "\n")
+		} else {
+			s.WriteString(line + helpStyle.Render(url) + "\n")
+		}
+	}
+
+	s.WriteString("\n" + helpStyle.Render(helpLine(
+		helpPair(keys.Up, keys.Down, "选择"),
+		helpAs(keys.Enter, "打开"),
+		"数字: 直接打开",
+		helpAs(keys.NewTab, "新标签页打开"),
+		helpAs(keys.Open, "在浏览器中打开"),
+		helpAs(keys.Back, "返回"),
+	)))
+	return s.String()
+}
+
+// browserOpenedMsg 在浏览器中打开链接的结果
+type browserOpenedMsg struct {
+	err error
+}
+
+// openInBrowser 在后台打开链接，等待打开命令时界面不会卡住
+func openInBrowser(url string) tea.Cmd {
+	return func() tea.Msg {
+		return browserOpenedMsg{err: browser.Open(url)}
+	}
+}
@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/render"
+)
+
+// doubleClickInterval 两次点击同一项的间隔小于该时间时视为双击
+const doubleClickInterval = 400 * time.Millisecond
+
+// handleMouse 处理鼠标事件：滚轮在各个列表中移动选中项或滚动内容，左键点击选中、双击打开
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	wheel := 0
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		wheel = -1
+	case tea.MouseButtonWheelDown:
+		wheel = 1
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+	default:
+		return m, nil
+	}
+
+	switch m.state {
+	case topicListView:
+		var next tea.Model
+		var cmd tea.Cmd
+		if wheel != 0 {
+			next, cmd = m.moveTopicSelection(wheel)
+		} else {
+			next, cmd = m.clickTopicList(msg.X, msg.Y)
+		}
+		if nm, ok := next.(Model); ok && nm.state == topicListView {
+			return nm, tea.Batch(cmd, nm.schedulePreview())
+		}
+		return next, cmd
+	case topicDetailView:
+		if wheel != 0 {
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+		return m.clickTopicDetail(msg.X, msg.Y)
+	case searchResultView:
+		if wheel != 0 {
+			m.selected = max(min(m.selected+wheel, len(m.searchResults)-1), 0)
+			return m, nil
+		}
+		return m.clickSearchResult(msg.X, msg.Y)
+	case userProfileView:
+		m.profileIdx = max(min(m.profileIdx+wheel, len(m.profileActions)-1), 0)
+	case linkPickerView:
+		m.linkIdx = max(min(m.linkIdx+wheel, len(m.links)-1), 0)
+	case paletteView:
+		m.paletteIdx = max(min(m.paletteIdx+wheel, len(m.paletteMatches())-1), 0)
+	case helpView:
+		var cmd tea.Cmd
+		m.helpViewport, cmd = m.helpViewport.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// doubleClick 记录一次点击，与上一次点击的是同一项且间隔足够短时返回 true
+func (m *Model) doubleClick(idx int) bool {
+	now := time.Now()
+	double := idx == m.lastClickIdx && now.Sub(m.lastClickAt) < doubleClickInterval
+	m.lastClickIdx = idx
+	m.lastClickAt = now
+	if double {
+		// 第三次点击重新开始计算
+		m.lastClickAt = time.Time{}
+	}
+	return double
+}
+
+// errLines 启动警告和错误信息在列表上方占用的行数
+func (m Model) errLines() int {
+	lines := strings.Count(m.renderWarnings(), "\n")
+	if m.err != nil {
+		lines += strings.Count(m.err.Error(), "\n") + 2
+	}
+	return lines
+}
+
+// clickTopicList 点击标题中的过滤标签切换列表，点击标签栏切换标签页；
+// 单击话题选中，双击打开，分栏布局下点击右侧预览打开选中的话题
+func (m Model) clickTopicList(x, y int) (tea.Model, tea.Cmd) {
+	switch y {
+	case 0:
+		if f := m.filterAt(x); f != "" && (f != m.filter || m.category != nil) {
+			m.filter = f
+			m.category = nil
+			return m.reloadTopics()
+		}
+		return m, nil
+	case 1:
+		return m.clickTabBar(x)
+	}
+
+	if m.splitView() && x >= m.splitLeftWidth() {
+		if len(m.topics) > 0 {
+			m.state = topicDetailView
+			return m, m.fetchTopicDetail(m.topics[m.selected].ID)
+		}
+		return m, nil
+	}
+
+	start, end, _ := m.topicListWindow()
+	idx := start + y - 2 - m.errLines()
+	if idx < start || idx >= end {
+		return m, nil
+	}
+	m.selected = idx
+	if m.doubleClick(idx) {
+		m.state = topicDetailView
+		return m, m.fetchTopicDetail(m.topics[idx].ID)
+	}
+	return m, nil
+}
+
+// filterAt 返回话题列表标题中第 x 列所在的过滤标签，不在任何标签上时返回空字符串
+func (m Model) filterAt(x int) string {
+	left := lipgloss.Width(titleStyle.Render(m.listTitle())) + 1
+	for _, f := range topicFilters {
+		right := left + lipgloss.Width(" "+f+" ")
+		if x >= left && x < right {
+			return f
+		}
+		left = right + 1
+	}
+	return ""
+}
+
+// clickTabBar 点击标签栏中的标签切换到对应的标签页
+func (m Model) clickTabBar(x int) (tea.Model, tea.Cmd) {
+	pos := m.tabAt(x)
+	if pos < 0 {
+		return m, nil
+	}
+	return m.switchTab(pos), nil
+}
+
+// clickSearchResult 单击搜索结果选中，双击打开对应的话题
+func (m Model) clickSearchResult(x, y int) (tea.Model, tea.Cmd) {
+	if y == 1 {
+		return m.clickTabBar(x)
+	}
+	start, end := m.searchResultWindow()
+	row := y - 2 - m.errLines()
+	if row < 0 || row%searchLinesPerResult == searchLinesPerResult-1 {
+		return m, nil
+	}
+	idx := start + row/searchLinesPerResult
+	if idx >= end {
+		return m, nil
+	}
+	m.selected = idx
+	if m.doubleClick(-1 - idx) {
+		m.state = topicDetailView
+		return m, m.fetchTopicDetail(m.searchResults[idx].TopicID)
+	}
+	return m, nil
+}
+
+// clickTopicDetail 点击帖子将光标移到该帖子，之后的点赞、回复等操作都针对它；
+// 点击帖子中的链接时打开链接
+func (m Model) clickTopicDetail(x, y int) (tea.Model, tea.Cmd) {
+	if m.topicDetail == nil {
+		if y == 0 {
+			return m.clickTabBar(x)
+		}
+		return m, nil
+	}
+	if y == 1 {
+		return m.clickTabBar(x)
+	}
+
+	row := y - 2
+	if row < 0 || row >= m.viewport.Height || len(m.postOffsets) == 0 {
+		return m, nil
+	}
+	line := m.viewport.YOffset + row
+	if line >= m.viewport.TotalLineCount() {
+		return m, nil
+	}
+	idx := 0
+	for i, offset := range m.postOffsets {
+		if offset <= line {
+			idx = i
+		}
+	}
+
+	link := m.linkAt(line, x)
+
+	if idx != m.currentPostIdx {
+		m.currentPostIdx = idx
+		m.refreshTopicDetail()
+	}
+
+	if link != nil && link.post < len(m.posts) {
+		m.links = render.Parse(m.posts[link.post].Cooked).Links()
+		return m.followLink(link.Link)
+	}
+	return m, nil
+}
+
+// postLink 帖子正文中一段链接文字在视口内容中的位置
+type postLink struct {
+	post int // 帖子在 m.posts 中的下标
+	render.LinkSpan
+}
+
+// linkAt 返回视口内容第 line 行第 col 列所在的链接，不在链接上时返回 nil
+func (m Model) linkAt(line, col int) *postLink {
+	for i := range m.postLinks {
+		l := &m.postLinks[i]
+		if l.Line == line && col >= l.Start && col < l.End {
+			return l
+		}
+	}
+	return nil
+}
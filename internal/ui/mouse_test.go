@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/config"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
+)
+
+func TestLinkAt(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+
+	m := Model{
+		config: &config.Config{},
+		width:  80,
+		bodies: newBodyStore(),
+		images: newImageStore(),
+		posts: []client.Post{
+			{ID: 1, PostNumber: 1, Username: "a", Cooked: `<p>见<a href="https://a.example">这里</a></p>`},
+			{ID: 2, PostNumber: 2, Username: "b", Cooked: `<p>这里 <a href="https://b.example">x</a> 和 <a href="https://c.example">这里</a></p>`},
+		},
+	}
+	content, _, links := m.renderTopicDetail()
+	m.postLinks = links
+
+	// 找到第二个帖子中作为链接的“这里”：该行第二次出现的位置
+	lines := strings.Split(content, "\n")
+	line := -1
+	for i, l := range lines {
+		if strings.HasPrefix(l, "这里 x") {
+			line = i
+		}
+	}
+	if line < 0 {
+		t.Fatalf("没有找到第二个帖子的正文:\n%s", content)
+	}
+	col := runewidth.StringWidth(lines[line][:strings.LastIndex(lines[line], "这里")])
+
+	tests := []struct {
+		name string
+		col  int
+		post int
+		link int
+		ok   bool
+	}{
+		{"普通文字", 0, 0, 0, false},
+		{"短链接", runewidth.StringWidth("这里 "), 1, 0, true},
+		{"同名链接的第一列", col, 1, 1, true},
+		{"宽字符的第二列", col + 1, 1, 1, true},
+		{"链接之后", col + 4, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.linkAt(line, tt.col)
+			if !tt.ok {
+				if got != nil {
+					t.Fatalf("linkAt = %+v，应为 nil", *got)
+				}
+				return
+			}
+			if got == nil || got.post != tt.post || got.Link != tt.link {
+				t.Fatalf("linkAt = %+v，应为帖子 %d 的第 %d 个链接", got, tt.post, tt.link)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/client"
+)
+
+// periodLabels 排行榜时间范围的名称
+var periodLabels = map[string]string{
+	"daily":     "今日",
+	"weekly":    "本周",
+	"monthly":   "本月",
+	"quarterly": "本季度",
+	"yearly":    "今年",
+	"all":       "全部",
+}
+
+// orderLabels 排序字段的名称
+var orderLabels = map[string]string{
+	"activity": "最新活动",
+	"views":    "浏览数",
+	"likes":    "点赞数",
+	"posts":    "帖子数",
+	"created":  "创建时间",
+}
+
+// serverSorted 当前列表是否由服务器排序：最新话题和分类列表支持排序参数，其他列表在本地排序已加载的话题
+func (m Model) serverSorted() bool {
+	return m.category != nil || m.filter == "latest"
+}
+
+// cyclePeriod 切换排行榜的时间范围，不在排行榜时先切换到排行榜
+func (m Model) cyclePeriod() (tea.Model, tea.Cmd) {
+	if m.filter != "top" || m.category != nil {
+		return m.setPeriod(m.period)
+	}
+	for i, p := range client.TopPeriods {
+		if p == m.period {
+			return m.setPeriod(client.TopPeriods[(i+1)%len(client.TopPeriods)])
+		}
+	}
+	return m.setPeriod(client.TopPeriods[0])
+}
+
+// setPeriod 显示指定时间范围的排行榜
+func (m Model) setPeriod(period string) (tea.Model, tea.Cmd) {
+	m.filter = "top"
+	m.category = nil
+	m.period = period
+	m.searchResults = nil
+	return m.reloadTopics()
+}
+
+// cycleOrder 依次切换排序字段：默认顺序 → 最新活动 → 浏览数 → 点赞数 → 帖子数 → 创建时间 → 默认顺序
+func (m Model) cycleOrder() (tea.Model, tea.Cmd) {
+	next := client.TopicOrder{By: client.TopicOrders[0], Ascending: m.order.Ascending}
+	for i, o := range client.TopicOrders {
+		if o == m.order.By {
+			next.By = ""
+			if i+1 < len(client.TopicOrders) {
+				next.By = client.TopicOrders[i+1]
+			}
+		}
+	}
+	if next.By == "" {
+		next.Ascending = false
+	}
+	return m.setOrder(next)
+}
+
+// toggleAscending 切换升序 / 降序
+func (m Model) toggleAscending() (tea.Model, tea.Cmd) {
+	if m.order.By == "" {
+		m.notice = "默认顺序不能切换升降序，按 " + keys.Sort.Help().Key + " 选择排序字段"
+		return m, nil
+	}
+	order := m.order
+	order.Ascending = !order.Ascending
+	return m.setOrder(order)
+}
+
+// setOrder 设置排序方式：服务器排序的列表重新加载，其他列表在本地重新排序
+func (m Model) setOrder(order client.TopicOrder) (tea.Model, tea.Cmd) {
+	m.order = order
+	m.notice = ""
+	m.searchResults = nil
+	m.state = topicListView
+	// 本地排序后无法恢复服务器的默认顺序，需要重新加载
+	if m.serverSorted() || order.By == "" {
+		return m.reloadTopics()
+	}
+	m.sortTopics()
+	return m, m.schedulePreview()
+}
+
+// localSorted 当前列表是否在本地排序
+func (m Model) localSorted() bool {
+	return !m.serverSorted() && m.order.By != ""
+}
+
+// sortTopics 在本地排序已加载的话题，选中的话题保持不变
+func (m *Model) sortTopics() {
+	if !m.localSorted() {
+		return
+	}
+	selected := m.selectedTopicID()
+	client.SortTopics(m.topics, m.order)
+	for i, topic := range m.topics {
+		if topic.ID == selected {
+			m.selected = i
+			break
+		}
+	}
+}
+
+// orderStatus 状态栏中显示的排行榜时间范围和排序方式
+func (m Model) orderStatus() string {
+	var parts []string
+	if m.category == nil && m.filter == "top" {
+		parts = append(parts, "📅 "+periodLabels[m.period])
+	}
+	if m.order.By != "" {
+		dir := "↓"
+		if m.order.Ascending {
+			dir = "↑"
+		}
+		parts = append(parts, "↕ "+orderLabels[m.order.By]+" "+dir)
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/config"
+)
+
+func topicIDs(topics []client.Topic) []int {
+	var ids []int
+	for _, topic := range topics {
+		ids = append(ids, topic.ID)
+	}
+	return ids
+}
+
+func TestLoadMoreSortsOnlyNewPage(t *testing.T) {
+	m := Model{
+		config: &config.Config{},
+		filter: "hot",
+		order:  client.TopicOrder{By: "views"},
+		users:  map[int]string{},
+	}
+	load := func(more bool, topics ...client.Topic) {
+		t.Helper()
+		next, _ := m.Update(topicListMsg{topics: topics, users: map[int]string{}, append: more})
+		m = next.(Model)
+	}
+
+	load(false, client.Topic{ID: 1, Views: 10}, client.Topic{ID: 2, Views: 30})
+	if got, want := topicIDs(m.topics), []int{2, 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("首次加载后 = %v，应为 %v", got, want)
+	}
+	m.selected = 1
+
+	// 新一页中浏览数更高的话题不会插到已列出的话题前面
+	load(true, client.Topic{ID: 3, Views: 5}, client.Topic{ID: 4, Views: 50})
+	if got, want := topicIDs(m.topics), []int{2, 1, 4, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("加载更多后 = %v，应为 %v", got, want)
+	}
+	if m.selected != 1 {
+		t.Fatalf("加载更多后 selected = %d，应保持为 1", m.selected)
+	}
+
+	// 服务器排序的列表保持服务器返回的顺序
+	m.filter = "latest"
+	load(true, client.Topic{ID: 5, Views: 1}, client.Topic{ID: 6, Views: 100})
+	if got, want := topicIDs(m.topics)[4:], []int{5, 6}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("服务器排序的新一页 = %v，应为 %v", got, want)
+	}
+}
@@ -0,0 +1,404 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/theme"
+)
+
+type categoriesMsg struct {
+	categories []client.Category
+	err        error
+}
+
+// paletteItem 命令面板中的一条命令
+type paletteItem struct {
+	name string // 命令名，也可以输入 "命令名 参数" 直接执行
+	desc string
+	arg  string // 参数提示，为空表示不需要参数
+	run  func(m Model, arg string) (tea.Model, tea.Cmd)
+}
+
+// paletteMatch 与输入匹配的命令及其参数
+type paletteMatch struct {
+	item  paletteItem
+	arg   string
+	score int
+}
+
+// openPalette 打开命令面板，首次打开时获取分类列表
+func (m Model) openPalette() (tea.Model, tea.Cmd) {
+	m.prevState = m.state
+	m.state = paletteView
+	m.paletteIdx = 0
+	m.notice = ""
+	m.paletteInput.Reset()
+	m.paletteInput.Focus()
+	cmds := []tea.Cmd{textarea.Blink}
+	if m.categories == nil && !m.loadingCats {
+		m.loadingCats = true
+		cmds = append(cmds, m.fetchCategories)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) fetchCategories() tea.Msg {
+	categories, err := m.client.GetCategories()
+	return categoriesMsg{categories: categories, err: err}
+}
+
+// paletteKey 判断按键是否触发命令面板中的操作，可以输入的字符（如 j/k）留给输入框
+func paletteKey(msg tea.KeyMsg, b key.Binding) bool {
+	return msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace && key.Matches(msg, b)
+}
+
+func (m Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	matches := m.paletteMatches()
+
+	switch {
+	case paletteKey(msg, keys.Back):
+		m.state = m.prevState
+		m.paletteInput.Blur()
+		return m, nil
+	case paletteKey(msg, keys.Up):
+		if m.paletteIdx > 0 {
+			m.paletteIdx--
+		}
+		return m, nil
+	case paletteKey(msg, keys.Down):
+		if m.paletteIdx < len(matches)-1 {
+			m.paletteIdx++
+		}
+		return m, nil
+	case msg.Type == tea.KeyTab:
+		// 补全选中的命令名，需要参数的命令补全后等待输入参数
+		if m.paletteIdx < len(matches) {
+			item := matches[m.paletteIdx].item
+			if item.arg != "" {
+				m.paletteInput.SetValue(item.name + " ")
+			} else {
+				m.paletteInput.SetValue(item.name)
+			}
+			m.paletteInput.CursorEnd()
+			m.paletteIdx = 0
+		}
+		return m, nil
+	case paletteKey(msg, keys.Enter):
+		if m.paletteIdx >= len(matches) {
+			return m, nil
+		}
+		match := matches[m.paletteIdx]
+		if match.item.arg != "" && match.arg == "" {
+			m.paletteInput.SetValue(match.item.name + " ")
+			m.paletteInput.CursorEnd()
+			m.paletteIdx = 0
+			return m, nil
+		}
+		m.state = m.prevState
+		m.paletteInput.Blur()
+		m.err = nil
+		return match.item.run(m, match.arg)
+	}
+
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.paletteIdx = 0
+	return m, cmd
+}
+
+// paletteItems 返回当前界面可用的命令
+func (m Model) paletteItems() []paletteItem {
+	items := []paletteItem{
+		{name: "open", desc: "打开话题", arg: "<话题ID>", run: paletteOpenTopic},
+		{name: "tab", desc: "在新标签页中打开话题", arg: "<话题ID>", run: func(m Model, arg string) (tea.Model, tea.Cmd) {
+			topicID, err := strconv.Atoi(arg)
+			if err != nil || topicID <= 0 {
+				m.notice = "无效的话题 ID: " + arg
+				return m, nil
+			}
+			return m.openInNewTab(topicID)
+		}},
+	}
+	if m.topicDetail != nil {
+		items = append(items,
+			paletteItem{name: "jump", desc: "跳转到楼层", arg: "<楼层>", run: paletteJump},
+			paletteItem{name: "find", desc: "在当前话题中搜索", arg: "<关键词>", run: func(m Model, arg string) (tea.Model, tea.Cmd) {
+				return m.find(arg)
+			}},
+		)
+	}
+	items = append(items, paletteItem{name: "search", desc: "全站搜索", arg: "<关键词>", run: func(m Model, arg string) (tea.Model, tea.Cmd) {
+		return m.search(arg)
+	}})
+
+	filters := []struct{ name, desc string }{
+		{"latest", "最新话题"},
+		{"hot", "热门话题"},
+		{"new", "新话题"},
+		{"top", "排行榜"},
+	}
+	for _, f := range filters {
+		filter := f.name
+		items = append(items, paletteItem{
+			name: "filter " + filter,
+			desc: getFilterEmoji(filter) + " " + f.desc,
+			run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+				m.filter = filter
+				m.category = nil
+				m.searchResults = nil
+				return m.reloadTopics()
+			},
+		})
+	}
+
+	for _, p := range client.TopPeriods {
+		period := p
+		items = append(items, paletteItem{
+			name: "period " + period,
+			desc: "📊 " + periodLabels[period] + "排行榜",
+			run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+				return m.setPeriod(period)
+			},
+		})
+	}
+	items = append(items, paletteItem{name: "sort default", desc: "↕ 默认顺序", run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+		return m.setOrder(client.TopicOrder{})
+	}})
+	for _, o := range client.TopicOrders {
+		by := o
+		items = append(items, paletteItem{
+			name: "sort " + by,
+			desc: "↕ 按" + orderLabels[by] + "排序",
+			run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+				return m.setOrder(client.TopicOrder{By: by, Ascending: m.order.Ascending})
+			},
+		})
+	}
+	items = append(items, paletteItem{name: "reverse", desc: "↕ 切换升序 / 降序", run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+		return m.toggleAscending()
+	}})
+
+	for i := range m.categories {
+		cat := m.categories[i]
+		items = append(items, paletteItem{
+			name: "category " + cat.Slug,
+			desc: fmt.Sprintf("📂 %s（%d 个话题）", cat.Name, cat.TopicCount),
+			run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+				m.category = &cat
+				m.searchResults = nil
+				return m.reloadTopics()
+			},
+		})
+	}
+
+	items = append(items, paletteItem{
+		name: "theme",
+		desc: fmt.Sprintf("🎨 切换到下一个主题（当前 %s）", activeTheme.Name),
+		run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+			return m.paletteSetTheme(m.nextTheme())
+		},
+	})
+	for _, name := range theme.Names(m.config.UI.Themes) {
+		themeName := name
+		items = append(items, paletteItem{
+			name: "theme " + themeName,
+			desc: "🎨 使用主题 " + themeName,
+			run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+				return m.paletteSetTheme(themeName)
+			},
+		})
+	}
+
+	items = append(items, paletteItem{name: "layout", desc: "切换话题列表的分栏预览", run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+		m.state = topicListView
+		return m.toggleLayout()
+	}})
+	items = append(items, paletteItem{name: "refresh", desc: "刷新话题列表", run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+		m.searchResults = nil
+		return m.reloadTopics()
+	}})
+	if m.topicDetail != nil && m.prevState == topicDetailView {
+		items = append(items, paletteItem{name: "images", desc: "开关图片预览", run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+			return m.toggleImages()
+		}})
+	}
+	items = append(items,
+		paletteItem{name: "help", desc: "快捷键帮助", run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+			return m.openHelp(), nil
+		}},
+		paletteItem{name: "quit", desc: "退出", run: func(m Model, _ string) (tea.Model, tea.Cmd) {
+			return m, tea.Quit
+		}},
+	)
+	return items
+}
+
+func paletteOpenTopic(m Model, arg string) (tea.Model, tea.Cmd) {
+	topicID, err := strconv.Atoi(arg)
+	if err != nil || topicID <= 0 {
+		m.notice = "无效的话题 ID: " + arg
+		return m, nil
+	}
+	m.state = topicDetailView
+	m.searchResults = nil
+	return m, m.fetchTopicDetail(topicID)
+}
+
+func paletteJump(m Model, arg string) (tea.Model, tea.Cmd) {
+	floor, err := strconv.Atoi(arg)
+	if err != nil || floor <= 0 {
+		m.notice = "无效的楼层: " + arg
+		return m, nil
+	}
+	m.state = topicDetailView
+	return m, m.jumpToFloor(floor)
+}
+
+func (m Model) paletteSetTheme(name string) (tea.Model, tea.Cmd) {
+	if err := m.setTheme(name); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.notice = "🎨 已切换到主题 " + name
+	return m, nil
+}
+
+// paletteMatches 根据输入筛选命令：
+// "命令名 参数" 直接匹配需要参数的命令，纯数字可以打开话题或跳转楼层，其他输入按模糊匹配排序
+func (m Model) paletteMatches() []paletteMatch {
+	items := m.paletteItems()
+	input := strings.TrimSpace(m.paletteInput.Value())
+	if input == "" {
+		matches := make([]paletteMatch, len(items))
+		for i, item := range items {
+			matches[i] = paletteMatch{item: item}
+		}
+		return matches
+	}
+
+	var exact []paletteMatch
+	name, arg, hasArg := strings.Cut(input, " ")
+	arg = strings.TrimSpace(arg)
+	_, numErr := strconv.Atoi(input)
+	for _, item := range items {
+		if item.arg == "" {
+			continue
+		}
+		if hasArg && arg != "" && strings.EqualFold(item.name, name) {
+			exact = append(exact, paletteMatch{item: item, arg: arg})
+		} else if numErr == nil && (item.name == "open" || item.name == "tab" || item.name == "jump") {
+			exact = append(exact, paletteMatch{item: item, arg: input})
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+
+	var matches []paletteMatch
+	for _, item := range items {
+		if score, ok := fuzzyScore(input, item.name+" "+item.desc); ok {
+			matches = append(matches, paletteMatch{item: item, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	return matches
+}
+
+// fuzzyScore 判断 pattern 的字符是否按顺序出现在 text 中（忽略大小写和空格）
+// 连续匹配和在单词开头的匹配得分更高
+func fuzzyScore(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+
+	score, last, ti := 0, -2, 0
+	for _, r := range p {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		for ti < len(t) && t[ti] != r {
+			ti++
+		}
+		if ti == len(t) {
+			return 0, false
+		}
+		score++
+		if ti == last+1 {
+			score += 5
+		}
+		if ti == 0 || strings.ContainsRune(" /-_", t[ti-1]) {
+			score += 3
+		}
+		last = ti
+		ti++
+	}
+	return score, true
+}
+
+func (m Model) renderPalette() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(" ⌘ 命令面板 ") + "\n\n")
+	s.WriteString(m.paletteInput.View() + "\n\n")
+
+	matches := m.paletteMatches()
+	if len(matches) == 0 {
+		s.WriteString(helpStyle.Render("  没有匹配的命令") + "\n")
+	}
+
+	maxVisible := max(m.height-9, 5)
+	start := 0
+	if m.paletteIdx >= maxVisible {
+		start = m.paletteIdx - maxVisible + 1
+	}
+	end := min(start+maxVisible, len(matches))
+
+	nameWidth := 0
+	for _, match := range matches[start:end] {
+		nameWidth = max(nameWidth, lipgloss.Width(paletteLabel(match)))
+	}
+	for i := start; i < end; i++ {
+		label := paletteLabel(matches[i])
+		pad := strings.Repeat(" ", nameWidth-lipgloss.Width(label))
+		if i == m.paletteIdx {
+			s.WriteString(selectedStyle.Render("▶ "+label+pad+"  "+matches[i].item.desc) + "\n")
+		} else {
+			s.WriteString("  " + label + pad + "  " + helpStyle.Render(matches[i].item.desc) + "\n")
+		}
+	}
+
+	if m.loadingCats {
+		s.WriteString(loadingStyle.Render("  正在加载分类...") + "\n")
+	}
+	if m.notice != "" {
+		s.WriteString(helpStyle.Render(m.notice) + "\n")
+	}
+
+	s.WriteString("\n" + helpStyle.Render(helpLine(
+		helpPair(keys.Up, keys.Down, "选择"),
+		"Tab: 补全",
+		helpAs(keys.Enter, "执行"),
+		helpAs(keys.Back, "关闭"),
+	)))
+	return s.String()
+}
+
+// paletteLabel 命令名和参数，参数未输入时显示提示
+func paletteLabel(match paletteMatch) string {
+	switch {
+	case match.arg != "":
+		return match.item.name + " " + match.arg
+	case match.item.arg != "":
+		return match.item.name + " " + match.item.arg
+	}
+	return match.item.name
+}
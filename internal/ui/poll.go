@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/render"
+)
+
+type pollVotedMsg struct {
+	postID   int
+	pollName string
+	poll     *client.Poll
+	votes    []string
+	err      error
+}
+
+func (m Model) currentPoll() (client.Post, client.Poll, bool) {
+	if len(m.posts) <= m.currentPostIdx {
+		return client.Post{}, client.Poll{}, false
+	}
+	post := m.posts[m.currentPostIdx]
+	if len(post.Polls) <= m.pollIdx {
+		return post, client.Poll{}, false
+	}
+	return post, post.Polls[m.pollIdx], true
+}
+
+func (m Model) updatePoll(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	post, poll, ok := m.currentPoll()
+	if !ok {
+		m.state = topicDetailView
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, keys.Back):
+		m.state = topicDetailView
+	case key.Matches(msg, keys.Up):
+		if m.pollCursor > 0 {
+			m.pollCursor--
+		}
+	case key.Matches(msg, keys.Down):
+		if m.pollCursor < len(poll.Options)-1 {
+			m.pollCursor++
+		}
+	case key.Matches(msg, keys.NextTab):
+		// 切换到同一帖子中的下一个投票
+		m.pollIdx = (m.pollIdx + 1) % len(post.Polls)
+		m.pollCursor = 0
+		m.pollChoices = votedChoices(post, post.Polls[m.pollIdx])
+	case key.Matches(msg, keys.Toggle):
+		if poll.IsMultiple() && len(poll.Options) > m.pollCursor {
+			id := poll.Options[m.pollCursor].ID
+			m.pollChoices[id] = !m.pollChoices[id]
+		}
+	case key.Matches(msg, keys.Enter):
+		if !poll.IsOpen() || len(poll.Options) == 0 {
+			return m, nil
+		}
+		var options []string
+		if poll.IsMultiple() {
+			for _, o := range poll.Options {
+				if m.pollChoices[o.ID] {
+					options = append(options, o.ID)
+				}
+			}
+		} else {
+			options = []string{poll.Options[m.pollCursor].ID}
+		}
+		if len(options) == 0 {
+			return m, nil
+		}
+		return m, m.votePoll(post.ID, poll.Name, options)
+	case key.Matches(msg, keys.Unvote):
+		if poll.IsOpen() && len(post.PollsVotes[poll.Name]) > 0 {
+			return m, m.removePollVote(post.ID, poll.Name)
+		}
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m Model) renderPollView() string {
+	var s strings.Builder
+
+	post, poll, ok := m.currentPoll()
+	if !ok {
+		return ""
+	}
+
+	title := fmt.Sprintf(" 📊 投票 - #%d楼 @%s ", post.PostNumber, post.Username)
+	if len(post.Polls) > 1 {
+		title += fmt.Sprintf("(%d/%d) ", m.pollIdx+1, len(post.Polls))
+	}
+	s.WriteString(titleStyle.Render(title) + "\n\n")
+
+	if m.err != nil {
+		s.WriteString(fmt.Sprintf("❌ 错误: %v\n\n", m.err))
+	}
+
+	s.WriteString(renderPoll(post, poll, m.pollCursor, m.pollChoices, m.width))
+	s.WriteString("\n")
+
+	var help []string
+	if poll.IsOpen() {
+		help = append(help, helpPair(keys.Up, keys.Down, "选择"))
+		if poll.IsMultiple() {
+			help = append(help, helpAs(keys.Toggle, "勾选"), helpAs(keys.Enter, "提交"))
+		} else {
+			help = append(help, helpAs(keys.Enter, "投票"))
+		}
+		help = append(help, helpAs(keys.Unvote, "撤回"))
+	}
+	if len(post.Polls) > 1 {
+		help = append(help, helpAs(keys.NextTab, "下一个投票"))
+	}
+	help = append(help, helpAs(keys.Back, "返回"))
+	s.WriteString(helpStyle.Render(helpLine(help...)))
+
+	return s.String()
+}
+
+// renderPoll 渲染投票选项和结果，cursor < 0 时为只读展示
+func renderPoll(post client.Post, poll client.Poll, cursor int, choices map[string]bool, width int) string {
+	var s strings.Builder
+
+	voted := post.PollsVotes[poll.Name]
+	showResults := poll.CanSeeResults(len(voted) > 0)
+
+	header := "📊 " + poll.Name
+	if poll.Title != "" {
+		header = "📊 " + stripHTMLTags(poll.Title)
+	}
+	status := fmt.Sprintf("%d 人参与", poll.Voters)
+	if !poll.IsOpen() {
+		status += " · 已结束"
+	} else if poll.IsMultiple() {
+		status += fmt.Sprintf(" · 多选 (%d-%d)", poll.Min, poll.Max)
+	}
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render(header) + "  " + helpStyle.Render(status) + "\n")
+
+	total := poll.TotalVotes()
+	barWidth := 20
+	if width > 100 {
+		barWidth = 30
+	}
+
+	for i, option := range poll.Options {
+		mark := "( )"
+		if poll.IsMultiple() {
+			mark = "[ ]"
+		}
+		if isVoted(voted, option.ID) || choices[option.ID] {
+			if poll.IsMultiple() {
+				mark = "[x]"
+			} else {
+				mark = "(•)"
+			}
+		}
+
+		line := fmt.Sprintf("%s %s", mark, strings.TrimSpace(render.Plain(render.Parse(option.HTML), render.Options{})))
+		if showResults {
+			percent := 0.0
+			if total > 0 {
+				percent = float64(option.Votes) * 100 / float64(total)
+			}
+			filled := int(percent / 100 * float64(barWidth))
+			bar := pollBarStyle.Render(strings.Repeat("█", filled)) + helpStyle.Render(strings.Repeat("░", barWidth-filled))
+			line = fmt.Sprintf("%s\n    %s %3.0f%% (%d)", line, bar, percent, option.Votes)
+		}
+
+		if i == cursor {
+			s.WriteString("▶ " + line + "\n")
+		} else {
+			s.WriteString("  " + line + "\n")
+		}
+	}
+
+	if !showResults {
+		s.WriteString(helpStyle.Render("  投票后可查看结果") + "\n")
+	}
+
+	return s.String()
+}
+
+func isVoted(voted []string, optionID string) bool {
+	for _, id := range voted {
+		if id == optionID {
+			return true
+		}
+	}
+	return false
+}
+
+// votedChoices 用当前用户已投的选项初始化多选状态
+func votedChoices(post client.Post, poll client.Poll) map[string]bool {
+	choices := make(map[string]bool)
+	for _, id := range post.PollsVotes[poll.Name] {
+		choices[id] = true
+	}
+	return choices
+}
+
+func (m Model) votePoll(postID int, pollName string, options []string) tea.Cmd {
+	return func() tea.Msg {
+		poll, votes, err := m.client.VotePoll(postID, pollName, options)
+		return pollVotedMsg{postID: postID, pollName: pollName, poll: poll, votes: votes, err: err}
+	}
+}
+
+func (m Model) removePollVote(postID int, pollName string) tea.Cmd {
+	return func() tea.Msg {
+		poll, err := m.client.RemovePollVote(postID, pollName)
+		return pollVotedMsg{postID: postID, pollName: pollName, poll: poll, err: err}
+	}
+}
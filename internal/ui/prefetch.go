@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/client"
+)
+
+const (
+	// postBatchSize 每次请求的帖子数
+	postBatchSize = 20
+	// prefetchBatches 阅读时在已加载的帖子之后预取的批数
+	prefetchBatches = 2
+	// maxParallelFetches 同时进行的帖子请求数上限
+	maxParallelFetches = 4
+	// jumpFillLimit 跳转的楼层与已加载的帖子相距不超过该数量时补齐中间的帖子，保持连续阅读；
+	// 更远时只加载目标楼层附近的帖子
+	jumpFillLimit = 200
+)
+
+// prefetchStore 后台预取但还没有显示的帖子，Model 按值复制时共享同一份。
+// 只保存当前话题的帖子，切换或关闭话题时丢弃
+type prefetchStore struct {
+	topic    int                 // posts 所属的话题
+	posts    map[int]client.Post // 按帖子 ID，显示后删除
+	pending  map[int]bool        // 正在预取的帖子 ID
+	awaiting int                 // 等待预取完成后继续加载更多的话题
+}
+
+func newPrefetchStore() *prefetchStore {
+	return &prefetchStore{
+		posts:   make(map[int]client.Post),
+		pending: make(map[int]bool),
+	}
+}
+
+// keep 切换到 topicID 话题，丢弃其他话题的预取结果；topicID 为 0 表示没有打开话题
+func (p *prefetchStore) keep(topicID int) {
+	if p.topic != topicID {
+		p.topic = topicID
+		clear(p.posts)
+	}
+}
+
+// consume 帖子已加入 m.posts，从预取结果中删除
+func (p *prefetchStore) consume(posts []client.Post) {
+	for _, post := range posts {
+		delete(p.posts, post.ID)
+	}
+}
+
+type prefetchedMsg struct {
+	topicID int
+	ids     []int
+	posts   []client.Post
+	err     error
+}
+
+// collectPosts 从已加载和已预取的帖子中找出 ids 对应的帖子，返回找到的帖子和需要请求的 ID
+func (m Model) collectPosts(ids []int) (map[int]client.Post, []int) {
+	wanted := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	have := make(map[int]client.Post)
+	for _, post := range m.posts {
+		if wanted[post.ID] {
+			have[post.ID] = post
+		}
+	}
+	var missing []int
+	for _, id := range ids {
+		if _, ok := have[id]; ok {
+			continue
+		}
+		if post, ok := m.prefetch.posts[id]; ok {
+			have[id] = post
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return have, missing
+}
+
+// newPosts 去掉已经在 m.posts 中的帖子
+func (m Model) newPosts(posts []client.Post) []client.Post {
+	loaded := make(map[int]bool, len(m.posts))
+	for _, post := range m.posts {
+		loaded[post.ID] = true
+	}
+	var fresh []client.Post
+	for _, post := range posts {
+		if !loaded[post.ID] {
+			fresh = append(fresh, post)
+		}
+	}
+	return fresh
+}
+
+// prefetchPosts 在后台预取已加载的帖子之后的几批帖子，已加载、已预取或正在预取的不再请求
+func (m Model) prefetchPosts() tea.Cmd {
+	if m.topicDetail == nil {
+		return nil
+	}
+	topicID := m.topicDetail.ID
+	m.prefetch.keep(topicID)
+	start := m.nextUnloadedIdx()
+	var cmds []tea.Cmd
+	for b := 0; b < prefetchBatches && start < len(m.allPostIDs); b++ {
+		end := min(start+postBatchSize, len(m.allPostIDs))
+		_, missing := m.collectPosts(m.allPostIDs[start:end])
+		start = end
+
+		var ids []int
+		for _, id := range missing {
+			if !m.prefetch.pending[id] {
+				m.prefetch.pending[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		cmds = append(cmds, func() tea.Msg {
+			posts, err := m.client.GetPostsByIDs(topicID, ids)
+			return prefetchedMsg{topicID: topicID, ids: ids, posts: posts, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// handlePrefetched 保存预取的帖子；预取失败时忽略，需要时会重新请求
+func (m Model) handlePrefetched(msg prefetchedMsg) (tea.Model, tea.Cmd) {
+	for _, id := range msg.ids {
+		delete(m.prefetch.pending, id)
+	}
+	// 预取期间已切换到其他话题时丢弃
+	if msg.err == nil && msg.topicID == m.prefetch.topic && m.isCurrentTopic(msg.topicID) {
+		for _, post := range msg.posts {
+			m.prefetch.posts[post.ID] = post
+		}
+	}
+
+	// 加载更多时下一批正在预取，预取完成后继续
+	if m.prefetch.awaiting == msg.topicID {
+		m.prefetch.awaiting = 0
+		if !m.isCurrentTopic(msg.topicID) {
+			m.loading = false
+			m.notice = ""
+			return m, nil
+		}
+		return m, m.loadMorePosts()
+	}
+	return m, nil
+}
+
+// fetchPostsParallel 按批并发获取帖子，同时进行的请求不超过 maxParallelFetches，结果按 ids 的顺序返回
+func fetchPostsParallel(c *client.Client, topicID int, ids []int) ([]client.Post, error) {
+	var batches [][]int
+	for start := 0; start < len(ids); start += postBatchSize {
+		batches = append(batches, ids[start:min(start+postBatchSize, len(ids))])
+	}
+
+	results := make([][]client.Post, len(batches))
+	errs := make([]error, len(batches))
+	sem := make(chan struct{}, maxParallelFetches)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch []int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i], errs[i] = c.GetPostsByIDs(topicID, batch)
+		}(i, batch)
+	}
+	wg.Wait()
+
+	var posts []client.Post
+	for i := range batches {
+		if errs[i] != nil {
+			return posts, errs[i]
+		}
+		posts = append(posts, results[i]...)
+	}
+	return posts, nil
+}
+
+// orderPosts 按 ids 的顺序排列帖子，没有获取到的跳过
+func orderPosts(ids []int, have map[int]client.Post, fetched []client.Post) []client.Post {
+	for _, post := range fetched {
+		have[post.ID] = post
+	}
+	posts := make([]client.Post, 0, len(ids))
+	for _, id := range ids {
+		if post, ok := have[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+	return posts
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/render"
+)
+
+// previewSplitWidth 终端宽度达到该值时预览与编辑区左右并排，否则上下排列
+const previewSplitWidth = 100
+
+// resizeComposer 根据预览布局调整编辑区大小
+func (m *Model) resizeComposer() {
+	width, height := m.width-8, m.height-15
+	if m.showPreview {
+		if m.width >= previewSplitWidth {
+			width = m.width/2 - 4
+		} else {
+			// 上下排列时编辑区与预览各占一半高度
+			height = height / 2
+		}
+	}
+	m.composer.SetWidth(width)
+	m.composer.SetHeight(max(height, 3))
+}
+
+// renderComposerBody 渲染编辑区，开启预览时附带渲染后的 Markdown
+func (m Model) renderComposerBody() string {
+	if !m.showPreview {
+		return m.composer.View()
+	}
+
+	height := m.height - 15
+	if height < 5 {
+		height = 5
+	}
+
+	if m.width >= previewSplitWidth {
+		width := m.width/2 - 4
+		preview := m.renderPreview(width, height)
+		return lipgloss.JoinHorizontal(lipgloss.Top, m.composer.View(), "  ", preview)
+	}
+
+	return m.composer.View() + "\n" + m.renderPreview(m.width-8, height/2)
+}
+
+// renderPreview 渲染预览框，超出高度的部分只显示末尾，便于跟随输入
+func (m Model) renderPreview(width, height int) string {
+	content := strings.TrimSpace(m.composer.Value())
+	var body string
+	if content == "" {
+		body = helpStyle.Render("(预览为空)")
+	} else {
+		lines := strings.Split(render.Styled(render.ParseMarkdown(content), m.renderOptions(width-2)), "\n")
+		if len(lines) > height {
+			lines = lines[len(lines)-height:]
+		}
+		body = strings.Join(lines, "\n")
+	}
+
+	title := helpStyle.Render("── 预览 ──")
+	return lipgloss.NewStyle().Width(width).Render(title + "\n" + body)
+}
+
+// renderOptions 帖子和预览共用的渲染选项
+func (m Model) renderOptions(width int) render.Options {
+	opts := render.Options{
+		Width:     width,
+		Highlight: m.config.UI.HighlightEnabled() && !activeTheme.Monochrome,
+		CodeStyle: m.config.UI.CodeStyle,
+	}
+	if opts.CodeStyle == "" {
+		opts.CodeStyle = activeTheme.CodeStyle
+	}
+	if m.showImages {
+		opts.Image = m.inlineImage
+	}
+	return opts
+}
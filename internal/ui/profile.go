@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/client"
+)
+
+type userProfileMsg struct {
+	profile *client.UserProfile
+	summary *client.UserSummary
+	actions []client.UserAction
+	err     error
+}
+
+type userActionsMsg struct {
+	actions []client.UserAction
+	err     error
+}
+
+func (m Model) updateUserProfile(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, keys.Back):
+		m.state = topicDetailView
+		m.err = nil
+	case key.Matches(msg, keys.Up):
+		if m.profileIdx > 0 {
+			m.profileIdx--
+		}
+	case key.Matches(msg, keys.Down):
+		if m.profileIdx < len(m.profileActions)-1 {
+			m.profileIdx++
+		}
+	case key.Matches(msg, keys.Enter):
+		// 打开动态对应的话题
+		if len(m.profileActions) > m.profileIdx {
+			m.state = topicDetailView
+			return m, m.fetchTopicDetail(m.profileActions[m.profileIdx].TopicID)
+		}
+	case key.Matches(msg, keys.NewTab):
+		if len(m.profileActions) > m.profileIdx {
+			return m.openInNewTab(m.profileActions[m.profileIdx].TopicID)
+		}
+	case key.Matches(msg, keys.Open):
+		if m.profile != nil {
+			return m, openInBrowser(m.client.UserURL(m.profile.Username))
+		}
+	case key.Matches(msg, keys.LoadMore):
+		if m.profile != nil {
+			return m, m.loadMoreUserActions(m.profile.Username, len(m.profileActions))
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderUserProfile() string {
+	var s strings.Builder
+
+	if m.err != nil {
+		s.WriteString(titleStyle.Render(" 👤 用户资料 ") + "\n\n")
+		s.WriteString(fmt.Sprintf("❌ 错误: %v\n\n", m.err))
+		s.WriteString(helpStyle.Render("Esc: 返回 | q: 退出"))
+		return s.String()
+	}
+
+	if m.profile == nil {
+		s.WriteString(titleStyle.Render(" 👤 用户资料 ") + "\n\n")
+		s.WriteString(loadingStyle.Render("加载中...") + "\n")
+		return s.String()
+	}
+
+	p := m.profile
+	title := fmt.Sprintf(" 👤 @%s ", p.Username)
+	if p.Name != "" {
+		title = fmt.Sprintf(" 👤 %s (@%s) ", p.Name, p.Username)
+	}
+	s.WriteString(titleStyle.Render(title) + "\n\n")
+
+	var badges []string
+	badges = append(badges, fmt.Sprintf("信任等级 %d", p.TrustLevel))
+	if p.Admin {
+		badges = append(badges, "管理员")
+	} else if p.Moderator {
+		badges = append(badges, "版主")
+	}
+	if p.Title != "" {
+		badges = append(badges, p.Title)
+	}
+	s.WriteString("🏷  " + strings.Join(badges, " · ") + "\n")
+	s.WriteString(fmt.Sprintf("📅 注册于 %s  👀 最后活跃 %s\n", formatDate(p.CreatedAt), formatDate(p.LastSeenAt)))
+	if p.Location != "" || p.Website != "" {
+		s.WriteString(fmt.Sprintf("📍 %s  🔗 %s\n", p.Location, p.Website))
+	}
+
+	bio := stripHTMLTags(p.BioCooked)
+	if bio == "" {
+		bio = stripHTMLTags(p.BioExcerpt)
+	}
+	if bio != "" {
+		s.WriteString("\n" + wrapText(bio, m.width-8))
+	}
+
+	if sum := m.profileSummary; sum != nil {
+		s.WriteString("\n")
+		s.WriteString(helpStyle.Render(fmt.Sprintf(
+			"话题 %d | 回复 %d | 获赞 %d | 送赞 %d | 访问 %d 天 | 阅读 %d 帖 | 阅读时长 %.1f 小时",
+			sum.TopicCount, sum.PostCount, sum.LikesReceived, sum.LikesGiven,
+			sum.DaysVisited, sum.PostsReadCount, float64(sum.TimeRead)/3600,
+		)) + "\n")
+	}
+
+	s.WriteString("\n" + titleStyle.Render(" 📝 最近动态 ") + "\n\n")
+
+	if len(m.profileActions) == 0 {
+		s.WriteString("暂无动态\n")
+	}
+
+	// 每条动态占2行
+	maxVisible := (m.height - 20) / 2
+	if maxVisible < 3 {
+		maxVisible = 3
+	}
+
+	start := 0
+	if m.profileIdx >= maxVisible {
+		start = m.profileIdx - maxVisible + 1
+	}
+	end := start + maxVisible
+	if end > len(m.profileActions) {
+		end = len(m.profileActions)
+	}
+
+	titleWidth := 60
+	if m.width > 100 {
+		titleWidth = m.width - 40
+	}
+
+	for i := start; i < end; i++ {
+		action := m.profileActions[i]
+		kind := "💬"
+		if action.ActionType == 4 {
+			kind = "📝"
+		}
+		line := fmt.Sprintf("%s %s  #%d楼  %s", kind, truncate(action.Title, titleWidth), action.PostNumber, formatDate(action.CreatedAt))
+		excerpt := "   " + truncate(stripHTMLTags(action.Excerpt), titleWidth)
+		if i == m.profileIdx {
+			s.WriteString(selectedStyle.Render(line) + "\n")
+		} else {
+			s.WriteString(line + "\n")
+		}
+		s.WriteString(helpStyle.Render(excerpt) + "\n")
+	}
+
+	s.WriteString("\n")
+	helpText := helpLine(
+		helpPair(keys.Up, keys.Down, "移动"),
+		keys.Enter.Help().Key+": 打开话题",
+		keys.LoadMore.Help().Key+": 更多动态",
+		helpKey(keys.Open),
+		helpKey(keys.Back),
+		helpKey(keys.Quit),
+	)
+	s.WriteString(helpStyle.Render(helpText))
+
+	return s.String()
+}
+
+func (m Model) fetchUserProfile(username string) tea.Cmd {
+	return func() tea.Msg {
+		profile, err := m.client.GetUserProfile(username)
+		if err != nil {
+			return userProfileMsg{err: err}
+		}
+
+		// 摘要和动态获取失败不影响资料展示
+		summary, _ := m.client.GetUserSummary(username)
+		actions, _ := m.client.GetUserActions(username, client.UserActionFilterPosts, 0)
+
+		return userProfileMsg{
+			profile: profile,
+			summary: summary,
+			actions: actions,
+		}
+	}
+}
+
+func (m Model) loadMoreUserActions(username string, offset int) tea.Cmd {
+	return func() tea.Msg {
+		actions, err := m.client.GetUserActions(username, client.UserActionFilterPosts, offset)
+		return userActionsMsg{actions: actions, err: err}
+	}
+}
+
+// formatDate 截取 ISO 时间中的日期部分
+func formatDate(t string) string {
+	if len(t) >= 10 {
+		return t[:10]
+	}
+	if t == "" {
+		return "-"
+	}
+	return t
+}
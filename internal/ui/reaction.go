@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/emoji"
+)
+
+type reactionsMsg struct {
+	reactions []string
+	err       error
+}
+
+type reactionToggledMsg struct {
+	post *client.Post
+	err  error
+}
+
+func (m Model) updateReactionPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Back):
+		m.state = topicDetailView
+	case key.Matches(msg, keys.Left):
+		if m.reactionIdx > 0 {
+			m.reactionIdx--
+		}
+	case key.Matches(msg, keys.Right):
+		if m.reactionIdx < len(m.reactions)-1 {
+			m.reactionIdx++
+		}
+	case key.Matches(msg, keys.Enter):
+		m.state = topicDetailView
+		if len(m.reactions) > m.reactionIdx && len(m.posts) > m.currentPostIdx {
+			return m, m.toggleReaction(m.posts[m.currentPostIdx].ID, m.reactions[m.reactionIdx])
+		}
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m Model) renderReactionPicker() string {
+	if len(m.reactions) == 0 {
+		return loadingStyle.Render("加载表情中...")
+	}
+
+	var current string
+	if len(m.posts) > m.currentPostIdx && m.posts[m.currentPostIdx].CurrentUserReaction != nil {
+		current = m.posts[m.currentPostIdx].CurrentUserReaction.ID
+	}
+
+	var items []string
+	for i, r := range m.reactions {
+		item := " " + emoji.Render(r) + " "
+		if r == current {
+			item = "[" + emoji.Render(r) + "]"
+		}
+		if i == m.reactionIdx {
+			item = selectedStyle.Render(item)
+		}
+		items = append(items, item)
+	}
+
+	var s strings.Builder
+	s.WriteString("表情回应: " + strings.Join(items, " ") + "  " + helpStyle.Render(":"+m.reactions[m.reactionIdx]+":") + "\n")
+	s.WriteString(helpStyle.Render(helpLine(
+		helpPair(keys.Left, keys.Right, "选择"),
+		helpAs(keys.Enter, "切换"),
+		helpAs(keys.Back, "取消"),
+	)))
+	return s.String()
+}
+
+// renderReactions 渲染帖子的表情回应统计，当前用户的回应高亮显示
+func renderReactions(post client.Post) string {
+	if len(post.Reactions) == 0 {
+		return ""
+	}
+
+	mine := lipgloss.NewStyle().Bold(true).Underline(true)
+
+	var parts []string
+	for _, r := range post.Reactions {
+		part := fmt.Sprintf("%s %d", emoji.Render(r.ID), r.Count)
+		if post.CurrentUserReaction != nil && post.CurrentUserReaction.ID == r.ID {
+			part = mine.Render(part)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "  ")
+}
+
+func (m Model) fetchReactions() tea.Msg {
+	reactions, err := m.client.GetAvailableReactions()
+	if len(reactions) > 0 {
+		// 获取站点配置失败时使用默认列表，不提示错误
+		err = nil
+	}
+	return reactionsMsg{reactions: reactions, err: err}
+}
+
+func (m Model) toggleReaction(postID int, reaction string) tea.Cmd {
+	return func() tea.Msg {
+		post, err := m.client.ToggleReaction(postID, reaction)
+		return reactionToggledMsg{post: post, err: err}
+	}
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/state"
+)
+
+// restoreState 恢复上次退出时的话题列表和正在阅读的话题
+func (m *Model) restoreState() {
+	if m.saved == nil {
+		m.saved = &state.State{Topics: make(map[int]state.Position)}
+	}
+	switch m.saved.Filter {
+	case "latest", "hot", "new", "top":
+		m.filter = m.saved.Filter
+	}
+	if client.ValidTopPeriod(m.saved.Period) {
+		m.period = m.saved.Period
+	}
+	if order, err := client.ParseTopicOrder(m.saved.Order, ""); err == nil {
+		order.Ascending = order.By != "" && m.saved.Ascending
+		m.order = order
+	}
+	if c := m.saved.Category; c != nil && c.ID != 0 {
+		m.category = &client.Category{ID: c.ID, Slug: c.Slug, Name: c.Name}
+	}
+	if m.saved.LastTopic != 0 {
+		m.state = topicDetailView
+	}
+}
+
+// rememberFloor 记录当前话题的阅读位置
+func (m *Model) rememberFloor() {
+	if m.topicDetail == nil || len(m.posts) == 0 {
+		return
+	}
+	m.saved.SetFloor(m.topicDetail.ID, m.posts[m.readingPostIdx()].PostNumber)
+}
+
+// readingPostIdx 返回正在阅读的帖子：光标所在的帖子在视口中可见时为光标所在的帖子，
+// 否则（用 ↑/↓、翻页或鼠标滚轮滚动后）为视口顶部的帖子
+func (m Model) readingPostIdx() int {
+	idx := min(m.currentPostIdx, len(m.posts)-1)
+	if len(m.postOffsets) != len(m.posts) {
+		return idx
+	}
+	top := m.viewport.YOffset
+	if start := m.postOffsets[idx]; start >= top && start < top+m.viewport.Height {
+		return idx
+	}
+	for i, offset := range m.postOffsets {
+		if offset > top {
+			break
+		}
+		idx = i
+	}
+	return idx
+}
+
+// checkpoint 切换或关闭话题时保存阅读进度，避免异常退出时丢失
+func (m *Model) checkpoint() {
+	if err := m.SaveState(); err != nil {
+		m.err = fmt.Errorf("保存阅读进度失败: %w", err)
+	}
+}
+
+// inTopic 当前是否在阅读话题（包括在话题中打开的输入框、选择器和帮助）
+func (m Model) inTopic() bool {
+	if m.topicDetail == nil {
+		return false
+	}
+	switch m.state {
+	case topicListView, searchInputView, searchResultView, userProfileView:
+		return false
+	case helpView, paletteView:
+		return m.prevState == topicDetailView
+	}
+	return true
+}
+
+// SaveState 保存阅读进度和界面状态，切换话题和退出 TUI 时调用
+func (m Model) SaveState() error {
+	m.rememberFloor()
+	m.saved.Filter = m.filter
+	m.saved.Period = m.period
+	m.saved.Order = m.order.By
+	m.saved.Ascending = m.order.Ascending
+	m.saved.Category = nil
+	if m.category != nil {
+		m.saved.Category = &state.Category{ID: m.category.ID, Slug: m.category.Slug, Name: m.category.Name}
+	}
+	m.saved.LastTopic = 0
+	if m.inTopic() {
+		m.saved.LastTopic = m.topicDetail.ID
+	}
+	return m.saved.Save()
+}
+
+// browseSearchHistory 在搜索输入框中切换到更早（older=true）或更近的搜索历史
+func (m *Model) browseSearchHistory(older bool) {
+	history := m.saved.Searches
+	if len(history) == 0 {
+		return
+	}
+	idx := -1
+	for i, q := range history {
+		if q == m.searchInput.Value() {
+			idx = i
+			break
+		}
+	}
+	if older {
+		idx = min(idx+1, len(history)-1)
+	} else {
+		idx--
+	}
+	m.searchInput.Reset()
+	if idx >= 0 {
+		m.searchInput.SetValue(history[idx])
+		m.searchInput.CursorEnd()
+	}
+}
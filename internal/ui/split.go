@@ -0,0 +1,251 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/render"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	// splitAutoWidth auto 布局下终端宽度达到该值时左右分栏
+	splitAutoWidth = 140
+	// splitMinWidth 终端宽度小于该值时即使设置了 split 也只显示列表
+	splitMinWidth = 80
+	// previewDelay 选中话题停留该时间后才加载预览，避免快速移动时发出大量请求
+	previewDelay = 200 * time.Millisecond
+)
+
+// topicPreview 分栏预览的内容：首帖和最新的几条回复
+type topicPreview struct {
+	posts []client.Post
+	err   error
+
+	// 上次渲染的结果，宽度、主题等没有变化时直接使用
+	key  bodyKey
+	text string
+}
+
+// failed 预览是否加载失败（没有任何帖子）
+func (p *topicPreview) failed() bool {
+	return len(p.posts) == 0 && p.err != nil
+}
+
+// body 渲染预览中的帖子，参数没有变化时使用上次的结果，避免每次刷新界面都重新解析
+func (p *topicPreview) body(opts render.Options) string {
+	key := bodyKey{
+		width:     opts.Width,
+		theme:     activeTheme.Name,
+		highlight: opts.Highlight,
+		codeStyle: opts.CodeStyle,
+		images:    -1,
+	}
+	if p.text != "" && p.key == key {
+		return p.text
+	}
+
+	var s strings.Builder
+	for i, post := range p.posts {
+		if i == 1 {
+			s.WriteString("\n" + helpStyle.Render("── 最新回复 ──") + "\n\n")
+		} else if i > 1 {
+			s.WriteString("\n")
+		}
+		s.WriteString(authorStyle.Render(fmt.Sprintf("👤 @%s  #%d", post.Username, post.PostNumber)) + "\n")
+		s.WriteString(render.Styled(render.Parse(post.Cooked), opts) + "\n")
+	}
+	p.key, p.text = key, s.String()
+	return p.text
+}
+
+// previewStore 话题预览缓存，在 Model 的副本之间共享
+type previewStore struct {
+	previews map[int]*topicPreview
+	pending  map[int]bool
+}
+
+func newPreviewStore() *previewStore {
+	return &previewStore{
+		previews: make(map[int]*topicPreview),
+		pending:  make(map[int]bool),
+	}
+}
+
+// reset 清空缓存，刷新话题列表时调用
+func (p *previewStore) reset() {
+	p.previews = make(map[int]*topicPreview)
+	p.pending = make(map[int]bool)
+}
+
+// dropFailed 丢弃除 keep 以外加载失败的预览，重新选中这些话题时再次加载
+func (p *previewStore) dropFailed(keep int) {
+	for id, preview := range p.previews {
+		if id != keep && preview.failed() {
+			delete(p.previews, id)
+		}
+	}
+}
+
+type previewTickMsg struct {
+	topicID int
+}
+
+type previewLoadedMsg struct {
+	topicID int
+	preview *topicPreview
+}
+
+// splitView 当前是否使用左右分栏布局
+func (m Model) splitView() bool {
+	switch m.layout {
+	case "split":
+		return m.width >= splitMinWidth
+	case "single":
+		return false
+	default:
+		return m.width >= splitAutoWidth
+	}
+}
+
+// toggleLayout 在分栏和单栏之间切换，切换后不再根据宽度自动选择
+func (m Model) toggleLayout() (tea.Model, tea.Cmd) {
+	if m.splitView() {
+		m.layout = "single"
+	} else {
+		m.layout = "split"
+	}
+	return m, m.schedulePreview()
+}
+
+// selectedTopicID 返回话题列表中选中的话题，没有时返回 0
+func (m Model) selectedTopicID() int {
+	if m.selected < 0 || m.selected >= len(m.topics) {
+		return 0
+	}
+	return m.topics[m.selected].ID
+}
+
+// schedulePreview 选中的话题没有缓存时，延迟一段时间后加载预览
+func (m Model) schedulePreview() tea.Cmd {
+	topicID := m.selectedTopicID()
+	if !m.splitView() || topicID == 0 {
+		return nil
+	}
+	m.previews.dropFailed(topicID)
+	if _, ok := m.previews.previews[topicID]; ok || m.previews.pending[topicID] {
+		return nil
+	}
+	return tea.Tick(previewDelay, func(time.Time) tea.Msg {
+		return previewTickMsg{topicID: topicID}
+	})
+}
+
+// handlePreviewTick 选中的话题没有变化时开始加载预览
+func (m Model) handlePreviewTick(msg previewTickMsg) (tea.Model, tea.Cmd) {
+	if !m.splitView() || m.selectedTopicID() != msg.topicID {
+		return m, nil
+	}
+	if _, ok := m.previews.previews[msg.topicID]; ok || m.previews.pending[msg.topicID] {
+		return m, nil
+	}
+	m.previews.pending[msg.topicID] = true
+	return m, m.fetchPreview(m.topics[m.selected])
+}
+
+// fetchPreview 按楼层号获取首帖和最新回复。打开话题的接口会给话题记一次浏览，
+// 只是在列表中经过不应该算作浏览，因此不使用 GetTopic
+func (m Model) fetchPreview(topic client.Topic) tea.Cmd {
+	replies := m.config.UI.PreviewReplies
+	return func() tea.Msg {
+		first, err := m.client.GetPostByNumber(topic.ID, 1)
+		if err != nil {
+			return previewLoadedMsg{topicID: topic.ID, preview: &topicPreview{err: err}}
+		}
+		preview := &topicPreview{posts: []client.Post{*first}}
+
+		// 从最后一层往前取，被删除的楼层会获取失败，最多多试 replies 层
+		var latest []client.Post
+		for n := topic.HighestPostNumber; n > 1 && len(latest) < replies && topic.HighestPostNumber-n < 2*replies; n-- {
+			post, err := m.client.GetPostByNumber(topic.ID, n)
+			if err != nil {
+				preview.err = err
+				continue
+			}
+			latest = append(latest, *post)
+		}
+		slices.Reverse(latest)
+		preview.posts = append(preview.posts, latest...)
+		return previewLoadedMsg{topicID: topic.ID, preview: preview}
+	}
+}
+
+// splitLeftWidth 分栏布局下左侧话题列表的宽度
+func (m Model) splitLeftWidth() int {
+	return max(m.width*2/5, 40)
+}
+
+// renderSplitList 左侧为话题列表，右侧为选中话题的预览
+func (m Model) renderSplitList(start, end, height int) string {
+	leftWidth := m.splitLeftWidth()
+	rightWidth := m.width - leftWidth - 3
+	titleWidth := leftWidth - 14
+
+	var left strings.Builder
+	for i := start; i < end && i < len(m.topics); i++ {
+		topic := m.topics[i]
+		line := fmt.Sprintf("%3d. %s 💬%4d", i+1, padRight(runewidth.Truncate(topic.Title, titleWidth, "..."), titleWidth), topic.ReplyCount)
+		if i == m.selected {
+			left.WriteString(selectedStyle.Render(line) + "\n")
+		} else {
+			left.WriteString(line + "\n")
+		}
+	}
+
+	leftPane := lipgloss.NewStyle().Width(leftWidth).Height(height).Render(strings.TrimSuffix(left.String(), "\n"))
+	rightPane := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(activeTheme.Muted).
+		PaddingLeft(1).
+		Width(rightWidth).
+		Render(m.renderTopicPreview(rightWidth-1, height))
+	return lipgloss.JoinHorizontal(lipgloss.Top, leftPane, " ", rightPane) + "\n"
+}
+
+// renderTopicPreview 渲染选中话题的首帖和最新回复，超出高度的部分截断
+func (m Model) renderTopicPreview(width, height int) string {
+	if m.selected >= len(m.topics) {
+		return strings.Repeat("\n", height-1)
+	}
+	topic := m.topics[m.selected]
+
+	var s strings.Builder
+	s.WriteString(authorStyle.Copy().Bold(true).Width(width).Render(topic.Title) + "\n")
+	s.WriteString(helpStyle.Render(fmt.Sprintf("💬 %d 回复  👀 %d 浏览", topic.ReplyCount, topic.Views)) + "\n\n")
+
+	preview, ok := m.previews.previews[topic.ID]
+	switch {
+	case !ok:
+		s.WriteString(loadingStyle.Render("加载预览中..."))
+	case preview.failed():
+		s.WriteString(fmt.Sprintf("❌ 加载预览失败: %v", preview.err))
+	default:
+		opts := m.renderOptions(width - 2)
+		opts.Image = nil
+		s.WriteString(preview.body(opts))
+	}
+
+	lines := strings.Split(strings.TrimRight(s.String(), "\n"), "\n")
+	if len(lines) > height {
+		lines = append(lines[:height-1], helpStyle.Render(fmt.Sprintf("…（%s 查看全文）", keys.Enter.Help().Key)))
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
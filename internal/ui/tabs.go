@@ -0,0 +1,260 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/client"
+)
+
+// topicTab 一个话题标签页的阅读状态，当前标签页的状态保存在 Model 中，切换时才写回
+type topicTab struct {
+	id             int
+	detail         *client.TopicDetail
+	posts          []client.Post
+	allPostIDs     []int
+	currentPostIdx int
+	yOffset        int
+	findQuery      string
+}
+
+// activeTabID 返回当前标签页的 ID，没有打开的标签页时返回 0
+func (m Model) activeTabID() int {
+	if m.tabIdx < 0 || m.tabIdx >= len(m.tabs) {
+		return 0
+	}
+	return m.tabs[m.tabIdx].id
+}
+
+// isCurrentTopic 判断异步加载的结果是否属于当前显示的话题
+func (m Model) isCurrentTopic(topicID int) bool {
+	return m.topicDetail != nil && m.topicDetail.ID == topicID
+}
+
+// newTab 添加一个空标签页并切换过去，原标签页的状态会先保存
+func (m *Model) newTab() {
+	m.saveTab()
+	m.tabSeq++
+	m.tabs = append(m.tabs, topicTab{id: m.tabSeq})
+	m.tabIdx = len(m.tabs) - 1
+	m.topicDetail = nil
+	m.posts = nil
+	m.allPostIDs = nil
+	m.currentPostIdx = 0
+	m.findQuery = ""
+	m.notice = ""
+	m.prefetch.keep(0)
+}
+
+// saveTab 将当前话题的阅读状态写回所在的标签页
+func (m *Model) saveTab() {
+	if m.tabIdx < 0 || m.tabIdx >= len(m.tabs) {
+		return
+	}
+	m.rememberFloor()
+	tab := &m.tabs[m.tabIdx]
+	tab.detail = m.topicDetail
+	tab.posts = m.posts
+	tab.allPostIDs = m.allPostIDs
+	tab.currentPostIdx = m.currentPostIdx
+	tab.yOffset = m.viewport.YOffset
+	tab.findQuery = m.findQuery
+}
+
+// loadTab 切换到第 idx 个标签页，恢复滚动位置和光标
+func (m *Model) loadTab(idx int) {
+	m.saveTab()
+	tab := m.tabs[idx]
+	m.tabIdx = idx
+	m.topicDetail = tab.detail
+	m.posts = tab.posts
+	m.allPostIDs = tab.allPostIDs
+	m.currentPostIdx = tab.currentPostIdx
+	m.findQuery = tab.findQuery
+	m.notice = ""
+	m.err = nil
+	m.state = topicDetailView
+	if m.topicDetail == nil {
+		m.prefetch.keep(0)
+	} else {
+		m.prefetch.keep(m.topicDetail.ID)
+		m.refreshTopicDetail()
+		m.viewport.SetYOffset(tab.yOffset)
+	}
+}
+
+// storeTab 保存在后台标签页中加载完成的话题，标签页已关闭时丢弃；
+// 加载失败时保留标签页中原来的话题，为这次请求新建的空标签页直接关闭
+func (m *Model) storeTab(msg topicDetailMsg) {
+	if msg.err != nil {
+		for i, tab := range m.tabs {
+			if tab.id == msg.tabID && tab.detail == nil {
+				m.tabs = append(m.tabs[:i], m.tabs[i+1:]...)
+				if i < m.tabIdx {
+					m.tabIdx--
+				}
+				break
+			}
+		}
+		m.err = msg.err
+		return
+	}
+	if msg.detail == nil {
+		return
+	}
+	for i := range m.tabs {
+		if m.tabs[i].id == msg.tabID {
+			m.tabs[i] = topicTab{
+				id:         msg.tabID,
+				detail:     msg.detail,
+				posts:      msg.posts,
+				allPostIDs: msg.allPostIDs,
+			}
+			return
+		}
+	}
+}
+
+// openInNewTab 在新标签页中打开话题
+func (m Model) openInNewTab(topicID int) (tea.Model, tea.Cmd) {
+	m.newTab()
+	m.state = topicDetailView
+	return m, m.fetchTopicDetail(topicID)
+}
+
+// listState 话题列表标签页对应的界面，有搜索结果时显示搜索结果
+func (m Model) listState() viewState {
+	if len(m.searchResults) > 0 {
+		return searchResultView
+	}
+	return topicListView
+}
+
+// cycleTab 在话题列表和各个话题标签页之间循环切换
+func (m Model) cycleTab(dir int) Model {
+	if len(m.tabs) == 0 {
+		return m
+	}
+	pos := 0
+	if m.state == topicDetailView {
+		pos = m.tabIdx + 1
+	}
+	n := len(m.tabs) + 1
+	return m.switchTab(((pos+dir)%n + n) % n)
+}
+
+// switchTab 切换到标签栏中的第 pos 个标签，0 为话题列表
+func (m Model) switchTab(pos int) Model {
+	if pos == 0 {
+		m.saveTab()
+		m.state = m.listState()
+	} else {
+		m.loadTab(pos - 1)
+	}
+	m.checkpoint()
+	return m
+}
+
+// closeTab 关闭当前标签页，切换到相邻的标签页，全部关闭后回到话题列表
+func (m Model) closeTab() Model {
+	if m.tabIdx < 0 || m.tabIdx >= len(m.tabs) {
+		m.state = m.listState()
+		return m
+	}
+	m.rememberFloor()
+	closed := m.tabIdx
+	m.tabs = append(m.tabs[:closed], m.tabs[closed+1:]...)
+	m.tabIdx = -1
+	m.topicDetail = nil
+	m.posts = nil
+	m.allPostIDs = nil
+	m.findQuery = ""
+	m.prefetch.keep(0)
+	if len(m.tabs) == 0 {
+		m.state = m.listState()
+	} else {
+		m.loadTab(min(closed, len(m.tabs)-1))
+	}
+	m.checkpoint()
+	return m
+}
+
+// tabHelp 有打开的标签页时返回切换标签页的帮助
+func (m Model) tabHelp() string {
+	if len(m.tabs) == 0 {
+		return ""
+	}
+	return helpPair(keys.NextTab, keys.PrevTab, "切换标签页")
+}
+
+// tabBarLabels 返回标签栏中显示的标签和当前标签的位置，第一个标签为话题列表；
+// 标签太多时省略超出宽度的部分，more 为省略的数量
+func (m Model) tabBarLabels() (labels []string, active, more int) {
+	all := []string{"📋 列表"}
+	for i, tab := range m.tabs {
+		detail := tab.detail
+		if i == m.tabIdx {
+			detail = m.topicDetail
+		}
+		title := "加载中..."
+		if detail != nil {
+			title = detail.Title
+		}
+		all = append(all, fmt.Sprintf("%d %s", i+1, truncate(title, 14)))
+	}
+
+	if m.state == topicDetailView {
+		active = m.tabIdx + 1
+	}
+
+	width := 0
+	for i, label := range all {
+		w := lipgloss.Width(" " + label + " ")
+		if m.width > 0 && width+w > m.width-6 {
+			return labels, active, len(all) - i
+		}
+		width += w + 1
+		labels = append(labels, label)
+	}
+	return labels, active, 0
+}
+
+// renderTabBar 渲染标签栏，没有打开的话题时返回空字符串
+func (m Model) renderTabBar() string {
+	if len(m.tabs) == 0 {
+		return ""
+	}
+
+	labels, active, more := m.tabBarLabels()
+	var parts []string
+	for i, label := range labels {
+		if i == active {
+			parts = append(parts, selectedStyle.Render(" "+label+" "))
+		} else {
+			parts = append(parts, helpStyle.Render(" "+label+" "))
+		}
+	}
+	if more > 0 {
+		parts = append(parts, helpStyle.Render(fmt.Sprintf("+%d", more)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// tabAt 返回标签栏中第 x 列所在的标签位置（0 为话题列表），不在任何标签上时返回 -1
+func (m Model) tabAt(x int) int {
+	if len(m.tabs) == 0 {
+		return -1
+	}
+	labels, _, _ := m.tabBarLabels()
+	left := 0
+	for i, label := range labels {
+		right := left + lipgloss.Width(" "+label+" ")
+		if x >= left && x < right {
+			return i
+		}
+		left = right + 1
+	}
+	return -1
+}
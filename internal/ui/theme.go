@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lhpqaq/ldo/internal/config"
+	"github.com/lhpqaq/ldo/internal/render"
+	"github.com/lhpqaq/ldo/internal/theme"
+)
+
+// 界面样式随主题变化，由 applyTheme 设置
+var (
+	activeTheme    theme.Theme
+	titleStyle     lipgloss.Style
+	selectedStyle  lipgloss.Style
+	helpStyle      lipgloss.Style
+	loadingStyle   lipgloss.Style
+	authorStyle    lipgloss.Style // 帖子作者行
+	highlightStyle lipgloss.Style // 搜索结果中的关键词
+	cursorStyle    lipgloss.Style // 搜索结果左侧的光标
+	pollBarStyle   lipgloss.Style
+)
+
+func init() {
+	applyTheme(theme.Dark)
+}
+
+// LoadTheme 根据配置（ui.theme、ui.themes）加载主题，出错时使用自动检测的主题
+func LoadTheme(cfg config.UIConfig) error {
+	t, err := theme.Load(cfg)
+	applyTheme(t)
+	return err
+}
+
+// applyTheme 切换界面和帖子内容的配色
+func applyTheme(t theme.Theme) {
+	activeTheme = t
+
+	titleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.OnPrimary).
+		Background(t.Primary).
+		PaddingLeft(1).
+		PaddingRight(1)
+	selectedStyle = lipgloss.NewStyle().
+		Foreground(t.OnSelected).
+		Background(t.Selected)
+	helpStyle = lipgloss.NewStyle().Foreground(t.Muted)
+	loadingStyle = lipgloss.NewStyle().Foreground(t.Warning).Bold(true)
+	authorStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Primary)
+	highlightStyle = lipgloss.NewStyle().Foreground(t.Highlight).Bold(true)
+	cursorStyle = lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
+	pollBarStyle = lipgloss.NewStyle().Foreground(t.Primary)
+
+	// 单色主题没有背景色可用，标题和选中行改用反色
+	if t.Monochrome {
+		titleStyle = titleStyle.Reverse(true)
+		selectedStyle = selectedStyle.Reverse(true)
+		highlightStyle = highlightStyle.Underline(true)
+	}
+
+	render.SetTheme(t)
+}
+
+// setTheme 运行时切换到指定主题并重新渲染当前话题
+func (m *Model) setTheme(name string) error {
+	t, err := theme.Get(name, m.config.UI.Themes)
+	if err != nil {
+		return err
+	}
+	applyTheme(t)
+	if m.topicDetail != nil {
+		m.refreshTopicDetail()
+	}
+	return nil
+}
+
+// nextTheme 返回主题列表中当前主题的下一个
+func (m Model) nextTheme() string {
+	names := theme.Names(m.config.UI.Themes)
+	for i, name := range names {
+		if name == activeTheme.Name {
+			return names[(i+1)%len(names)]
+		}
+	}
+	return names[0]
+}
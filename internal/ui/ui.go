@@ -2,11 +2,10 @@ package ui
 
 import (
 	"fmt"
-	"os/exec"
 	"regexp"
-	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
@@ -14,6 +13,10 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lhpqaq/ldo/internal/client"
+	"github.com/lhpqaq/ldo/internal/config"
+	"github.com/lhpqaq/ldo/internal/editor"
+	"github.com/lhpqaq/ldo/internal/render"
+	"github.com/lhpqaq/ldo/internal/state"
 )
 
 type viewState int
@@ -25,18 +28,30 @@ const (
 	jumpInputView
 	searchInputView
 	searchResultView
+	userProfileView
+	reactionPickerView
+	pollView
+	flagView
+	editorConfirmView
+	linkPickerView
+	findInputView
+	helpView
+	paletteView
 )
 
 type Model struct {
 	client         *client.Client
+	config         *config.Config
 	state          viewState
 	topics         []client.Topic
 	users          map[int]string
 	selected       int
 	topicDetail    *client.TopicDetail
 	posts          []client.Post
-	allPostIDs     []int // 所有帖子的ID
-	currentPostIdx int   // 当前显示的帖子索引
+	allPostIDs     []int      // 所有帖子的ID
+	currentPostIdx int        // 光标所在的帖子索引
+	postOffsets    []int      // 每个帖子在视口内容中的起始行
+	postLinks      []postLink // 帖子正文中的链接在视口内容中的位置
 	viewport       viewport.Model
 	composer       textarea.Model
 	jumpInput      textarea.Model
@@ -52,63 +67,57 @@ type Model struct {
 	searchResults  []client.SearchResult
 	searchQuery    string
 	searchPage     int
+	profile        *client.UserProfile
+	profileSummary *client.UserSummary
+	profileActions []client.UserAction
+	profileIdx     int      // 用户动态列表中选中的索引
+	reactions      []string // 站点可用的表情回应
+	reactionIdx    int
+	pollIdx        int             // 当前帖子中正在操作的投票
+	pollCursor     int             // 投票选项光标
+	pollChoices    map[string]bool // 多选投票中勾选的选项
+	flagIdx        int
+	flagStep       int
+	flagInput      textarea.Model
+	notice         string // 状态栏提示信息
+	attachInput    textarea.Model
+	attaching      bool // 回复编辑器中正在输入附件路径
+	draftSequence  int
+	draftSaved     string // 最近一次保存到服务器的草稿内容
+	draftGen       int    // 每次打开编辑器递增，用于丢弃过期的自动保存定时器
+	editorContent  string // 外部编辑器返回的内容，等待确认发送
+	showPreview    bool   // 回复编辑器是否显示 Markdown 预览
+	showImages     bool   // 帖子中是否显示图片预览
+	images         *imageStore
+	bodies         *bodyStore
+	links          []render.LinkRef // 当前帖子中的链接
+	linkIdx        int
+	pendingFloor   int // 打开站内链接后需要跳转的楼层
+	findInput      textarea.Model
+	findQuery      string    // 话题内搜索的关键词，非空时高亮匹配
+	prevState      viewState // 打开帮助或命令面板前的界面
+	helpViewport   viewport.Model
+	paletteInput   textarea.Model
+	paletteIdx     int
+	categories     []client.Category
+	loadingCats    bool              // 正在获取分类列表
+	category       *client.Category  // 话题列表当前显示的分类，nil 表示按 filter 显示
+	period         string            // 排行榜的时间范围
+	order          client.TopicOrder // 话题列表的排序方式
+	tabs           []topicTab        // 打开的话题标签页，当前标签页的状态保存在上面的字段中
+	tabIdx         int               // 当前话题所在的标签页，-1 表示还没有打开话题
+	tabSeq         int
+	layout         string // 话题列表布局：auto、split、single
+	previews       *previewStore
+	prefetch       *prefetchStore
+	saved          *state.State // 跨会话保存的阅读进度和搜索历史
+	lastClickAt    time.Time    // 上一次鼠标点击的时间，用于识别双击
+	lastClickIdx   int          // 上一次点击的列表项
+	warnings       []string     // 启动时的配置警告，按任意键后清除
 }
 
-type keyMap struct {
-	Up       key.Binding
-	Down     key.Binding
-	Enter    key.Binding
-	Back     key.Binding
-	Quit     key.Binding
-	Filter   key.Binding
-	Reply    key.Binding
-	Like     key.Binding
-	Refresh  key.Binding
-	Open     key.Binding
-	LoadMore key.Binding
-	Jump     key.Binding
-	Last     key.Binding
-	Search   key.Binding
-}
-
-var keys = keyMap{
-	Up:       key.NewBinding(key.WithKeys("up", "k")),
-	Down:     key.NewBinding(key.WithKeys("down", "j")),
-	Enter:    key.NewBinding(key.WithKeys("enter")),
-	Back:     key.NewBinding(key.WithKeys("esc")),
-	Quit:     key.NewBinding(key.WithKeys("q", "ctrl+c")),
-	Filter:   key.NewBinding(key.WithKeys("f")),
-	Reply:    key.NewBinding(key.WithKeys("r")),
-	Like:     key.NewBinding(key.WithKeys("l")),
-	Refresh:  key.NewBinding(key.WithKeys("g")),
-	Open:     key.NewBinding(key.WithKeys("o")),
-	LoadMore: key.NewBinding(key.WithKeys("n")),
-	Jump:     key.NewBinding(key.WithKeys("/")),
-	Last:     key.NewBinding(key.WithKeys("G")),
-	Search:   key.NewBinding(key.WithKeys("s")),
-}
-
-var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color("#7D56F4")).
-			PaddingLeft(1).
-			PaddingRight(1)
-
-	selectedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#7D56F4"))
-
-	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888"))
-
-	loadingStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFA500")).
-			Bold(true)
-)
-
-func NewModel(c *client.Client) Model {
+// NewModel 创建 TUI 模型，warnings 为启动时读取配置产生的警告，显示在界面上直到按下任意键
+func NewModel(c *client.Client, cfg *config.Config, st *state.State, warnings ...error) Model {
 	ta := textarea.New()
 	ta.Placeholder = ""
 	ta.CharLimit = 0
@@ -131,22 +140,81 @@ func NewModel(c *client.Client) Model {
 	searchTA.SetHeight(1)
 	searchTA.ShowLineNumbers = false
 
-	vp := viewport.New(0, 0)
+	flagTA := textarea.New()
+	flagTA.Placeholder = ""
+	flagTA.CharLimit = 1000
+	flagTA.SetWidth(60)
+	flagTA.SetHeight(5)
+	flagTA.ShowLineNumbers = false
+
+	attachTA := textarea.New()
+	attachTA.Placeholder = "~/Pictures/screenshot.png"
+	attachTA.CharLimit = 1024
+	attachTA.SetWidth(60)
+	attachTA.SetHeight(1)
+	attachTA.ShowLineNumbers = false
+
+	findTA := textarea.New()
+	findTA.Placeholder = ""
+	findTA.CharLimit = 100
+	findTA.SetWidth(50)
+	findTA.SetHeight(1)
+	findTA.ShowLineNumbers = false
+	findTA.Prompt = "/"
+
+	paletteTA := textarea.New()
+	paletteTA.Placeholder = "输入命令或关键词"
+	paletteTA.CharLimit = 100
+	paletteTA.SetWidth(50)
+	paletteTA.SetHeight(1)
+	paletteTA.ShowLineNumbers = false
+	paletteTA.Prompt = ":"
 
-	return Model{
-		client:      c,
-		state:       topicListView,
-		filter:      "latest",
-		composer:    ta,
-		jumpInput:   jumpTA,
-		searchInput: searchTA,
-		viewport:    vp,
-		users:       make(map[int]string),
-		loading:     false,
+	vp := viewport.New(0, 0)
+	vp.KeyMap = keys.viewportKeyMap(keys.ScrollUp, keys.ScrollDown)
+	helpVP := viewport.New(0, 0)
+	helpVP.KeyMap = keys.viewportKeyMap(keys.Up, keys.Down)
+
+	m := Model{
+		client:       c,
+		config:       cfg,
+		showImages:   cfg.UI.InlineImages,
+		images:       newImageStore(),
+		bodies:       newBodyStore(),
+		state:        topicListView,
+		filter:       "latest",
+		period:       "weekly",
+		composer:     ta,
+		jumpInput:    jumpTA,
+		searchInput:  searchTA,
+		flagInput:    flagTA,
+		attachInput:  attachTA,
+		findInput:    findTA,
+		paletteInput: paletteTA,
+		viewport:     vp,
+		helpViewport: helpVP,
+		users:        make(map[int]string),
+		loading:      false,
+		tabIdx:       -1,
+		layout:       cfg.UI.Layout,
+		previews:     newPreviewStore(),
+		prefetch:     newPrefetchStore(),
+		saved:        st,
 	}
+	m.restoreState()
+	for _, err := range warnings {
+		if err != nil {
+			m.warnings = append(m.warnings, err.Error())
+		}
+	}
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
+	// 上次退出时正在阅读话题，直接打开
+	if m.state == topicDetailView && m.saved.LastTopic != 0 {
+		return tea.Batch(m.fetchTopics, m.fetchTopicDetail(m.saved.LastTopic))
+	}
 	return m.fetchTopics
 }
 
@@ -160,14 +228,44 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.viewport.Width = msg.Width - 4
 		m.viewport.Height = msg.Height - 10
-		m.composer.SetWidth(msg.Width - 8)
-		m.composer.SetHeight(msg.Height - 15)
+		m.helpViewport.Width = msg.Width - 4
+		m.helpViewport.Height = msg.Height - 5
+		m.paletteInput.SetWidth(min(msg.Width-4, 60))
+		m.resizeComposer()
 		m.ready = true
+		cmds = append(cmds, m.schedulePreview())
 
 	case tea.KeyMsg:
+		m.warnings = nil
+		// quit 可以重新绑定或禁用，Ctrl+C 总是可以退出
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		// 帮助和命令面板在各个浏览界面中都可以打开
+		switch m.state {
+		case topicListView, topicDetailView, searchResultView, userProfileView:
+			if key.Matches(msg, keys.Help) {
+				return m.openHelp(), nil
+			}
+			if key.Matches(msg, keys.Palette) {
+				return m.openPalette()
+			}
+			if key.Matches(msg, keys.NextTab) {
+				return m.cycleTab(1), nil
+			}
+			if key.Matches(msg, keys.PrevTab) {
+				return m.cycleTab(-1), nil
+			}
+		}
+
 		switch m.state {
 		case topicListView:
-			return m.updateTopicList(msg)
+			next, cmd := m.updateTopicList(msg)
+			// 分栏布局下选中的话题变化后加载预览
+			if nm, ok := next.(Model); ok && nm.state == topicListView {
+				return nm, tea.Batch(cmd, nm.schedulePreview())
+			}
+			return next, cmd
 		case topicDetailView:
 			return m.updateTopicDetail(msg)
 		case composerView:
@@ -178,53 +276,339 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateSearchInput(msg)
 		case searchResultView:
 			return m.updateSearchResult(msg)
+		case userProfileView:
+			return m.updateUserProfile(msg)
+		case reactionPickerView:
+			return m.updateReactionPicker(msg)
+		case pollView:
+			return m.updatePoll(msg)
+		case flagView:
+			return m.updateFlag(msg)
+		case editorConfirmView:
+			return m.updateEditorConfirm(msg)
+		case linkPickerView:
+			return m.updateLinkPicker(msg)
+		case findInputView:
+			return m.updateFindInput(msg)
+		case helpView:
+			return m.updateHelp(msg)
+		case paletteView:
+			return m.updatePalette(msg)
 		}
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case topicListMsg:
 		m.loading = false
 		if msg.append {
-			m.topics = append(m.topics, msg.topics...)
+			// 只排序新加载的一页，已列出的话题位置保持不变
+			page := filterMutedTopics(msg.topics)
+			if m.localSorted() {
+				client.SortTopics(page, m.order)
+			}
+			m.topics = append(m.topics, page...)
 			for k, v := range msg.users {
 				m.users[k] = v
 			}
 		} else {
-			m.topics = msg.topics
+			m.topics = filterMutedTopics(msg.topics)
 			m.users = msg.users
+			m.sortTopics()
+			m.selected = 0
 		}
 		m.moreTopicsURL = msg.moreURL
 		m.err = msg.err
+		cmds = append(cmds, m.schedulePreview())
+
+	case previewTickMsg:
+		return m.handlePreviewTick(msg)
+
+	case previewLoadedMsg:
+		delete(m.previews.pending, msg.topicID)
+		m.previews.previews[msg.topicID] = msg.preview
 
 	case topicDetailMsg:
+		// 加载期间已切换到其他标签页，结果保存到发起请求的标签页
+		if msg.tabID != m.activeTabID() {
+			m.storeTab(msg)
+			return m, nil
+		}
+		// 加载失败时保留标签页中原来的话题，为这次请求新建的空标签页直接关闭
+		if msg.err != nil {
+			m.pendingFloor = 0
+			if m.topicDetail == nil {
+				m = m.closeTab()
+			}
+			m.err = msg.err
+			return m, nil
+		}
+		if m.tabIdx < 0 {
+			m.newTab()
+		}
+		m.checkpoint()
+		prevTopic := 0
+		if m.topicDetail != nil {
+			prevTopic = m.topicDetail.ID
+		}
+		m.notice = ""
 		m.topicDetail = msg.detail
 		m.posts = msg.posts
 		m.allPostIDs = msg.allPostIDs
 		m.currentPostIdx = 0
-		m.err = msg.err
+		m.findQuery = ""
+		m.err = nil
 		if msg.detail != nil {
-			m.viewport.SetContent(m.renderTopicDetail())
+			m.refreshTopicDetail()
+			m.viewport.GotoTop()
+			cmds = append(cmds, m.loadPostImages())
+			// 回到上次阅读的楼层，通过站内链接打开时以链接指向的楼层为准
+			if floor := m.saved.Floor(msg.detail.ID); m.pendingFloor == 0 && floor > 1 {
+				m.pendingFloor = floor
+				if prevTopic != msg.detail.ID {
+					m.notice = fmt.Sprintf("📖 已回到上次阅读的 #%d 楼", floor)
+				}
+			}
+			// 通过站内链接打开时跳转到链接指向的楼层，否则从首页开始预取
+			if m.pendingFloor > 1 {
+				cmds = append(cmds, m.jumpToFloor(m.pendingFloor))
+			} else {
+				cmds = append(cmds, m.prefetchPosts())
+			}
 		}
+		m.pendingFloor = 0
 
 	case morePostsMsg:
-		if msg.err == nil && len(msg.posts) > 0 {
-			m.posts = append(m.posts, msg.posts...)
-			m.currentPostIdx = len(m.posts) - 1
-			m.viewport.SetContent(m.renderTopicDetail())
+		m.loading = false
+		m.notice = ""
+		if posts := m.newPosts(msg.posts); msg.err == nil && len(posts) > 0 && m.isCurrentTopic(msg.topicID) {
+			// 光标移到新加载的第一个帖子
+			m.currentPostIdx = len(m.posts)
+			m.posts = append(m.posts, posts...)
+			m.prefetch.consume(posts)
+			m.refreshTopicDetail()
+			m.scrollToCurrentPost()
+			cmds = append(cmds, m.loadPostImages(), m.prefetchPosts())
 		}
 		m.err = msg.err
 
 	case jumpToPostMsg:
-		if msg.err == nil && len(msg.posts) > 0 {
+		if msg.err == nil && len(msg.posts) > 0 && m.isCurrentTopic(msg.topicID) {
 			m.posts = msg.posts
 			m.currentPostIdx = msg.targetIdx
-			m.viewport.SetContent(m.renderTopicDetail())
+			m.prefetch.consume(msg.posts)
+			m.refreshTopicDetail()
+			m.scrollToCurrentPost()
+			cmds = append(cmds, m.loadPostImages(), m.prefetchPosts())
 		}
 		m.err = msg.err
 
+	case prefetchedMsg:
+		return m.handlePrefetched(msg)
+
 	case postCreatedMsg:
 		if msg.err == nil {
 			m.state = topicDetailView
 			m.composer.Reset()
-			return m, m.fetchTopicDetail(m.topicDetail.ID)
+			m.draftSaved = ""
+			return m, tea.Batch(m.fetchTopicDetail(m.topicDetail.ID), m.deleteDraft(m.topicDetail.ID))
+		}
+		m.err = msg.err
+
+	case draftLoadedMsg:
+		if msg.err == nil && msg.draft != nil {
+			m.draftSequence = msg.draft.Sequence
+			// 只在编辑器仍为空时恢复，避免覆盖已输入的内容
+			if m.state == composerView && m.topicDetail != nil && m.topicDetail.ID == msg.topicID &&
+				m.composer.Value() == "" && msg.draft.Reply != "" {
+				m.composer.SetValue(msg.draft.Reply)
+				m.replyToPost = msg.draft.ReplyToPostNumber
+				m.draftSaved = strings.TrimSpace(msg.draft.Reply)
+				m.notice = "📝 已恢复草稿"
+			}
+		}
+
+	case editorInitialMsg:
+		return m, openEditor(msg.content)
+
+	case editorFinishedMsg:
+		if msg.err != nil {
+			m.err = fmt.Errorf("编辑器 %s 运行失败: %w", editor.Name(), msg.err)
+			if m.state != composerView {
+				m.state = topicDetailView
+			}
+			return m, nil
+		}
+		if msg.content == "" {
+			m.state = topicDetailView
+			m.notice = "内容为空，已取消"
+			return m, nil
+		}
+		m.composer.Blur()
+		m.state = editorConfirmView
+		m.editorContent = msg.content
+		return m, nil
+
+	case imageLoadedMsg:
+		delete(m.images.pending, msg.url)
+		if msg.err != nil {
+			m.images.errs[msg.url] = msg.err
+		} else {
+			m.images.images[msg.url] = msg.img
+		}
+		m.images.version++
+		if m.state == topicDetailView && m.topicDetail != nil {
+			m.refreshTopicDetail()
+		}
+		return m, nil
+
+	case bookmarkToggledMsg:
+		if msg.err == nil {
+			for i := range m.posts {
+				if m.posts[i].ID == msg.postID {
+					m.posts[i].Bookmarked = msg.bookmarkID > 0
+					m.posts[i].BookmarkID = msg.bookmarkID
+				}
+			}
+			if msg.bookmarkID > 0 {
+				m.notice = "🔖 已收藏"
+			} else {
+				m.notice = "已取消收藏"
+			}
+			m.refreshTopicDetail()
+		}
+		m.err = msg.err
+
+	case findMoreMsg:
+		return m.handleFindMore(msg)
+
+	case categoriesMsg:
+		m.loadingCats = false
+		if msg.err == nil {
+			m.categories = msg.categories
+		} else {
+			m.notice = fmt.Sprintf("获取分类失败: %v", msg.err)
+		}
+
+	case osc52Msg:
+		return m, copyOSC52(msg)
+
+	case browserOpenedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			m.notice = "🌐 已在浏览器中打开"
+		}
+		return m, nil
+
+	case copiedMsg:
+		if msg.err != nil {
+			m.err = fmt.Errorf("复制%s失败: %w", msg.what, msg.err)
+		} else {
+			m.notice = "📋 已复制" + msg.what
+		}
+
+	case imageViewerClosedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		return m, nil
+
+	case draftTickMsg:
+		if msg.gen == m.draftGen && m.state == composerView {
+			return m, tea.Batch(m.saveDraftIfChanged(), draftTick(m.draftGen))
+		}
+
+	case draftSavedMsg:
+		if msg.err == nil {
+			m.draftSequence = msg.sequence
+			m.draftSaved = msg.content
+			if m.state == composerView {
+				m.notice = "💾 草稿已保存 " + time.Now().Format("15:04:05")
+			}
+		}
+
+	case notificationLevelMsg:
+		if msg.err == nil && m.topicDetail != nil && m.topicDetail.ID == msg.topicID {
+			m.topicDetail.SetNotificationLevel(msg.level)
+			for i := range m.topics {
+				if m.topics[i].ID == msg.topicID {
+					level := msg.level
+					m.topics[i].NotificationLevel = &level
+				}
+			}
+			m.topics = filterMutedTopics(m.topics)
+			if m.selected >= len(m.topics) && m.selected > 0 {
+				m.selected = len(m.topics) - 1
+			}
+		}
+		m.err = msg.err
+
+	case userProfileMsg:
+		if msg.err == nil {
+			m.profile = msg.profile
+			m.profileSummary = msg.summary
+			m.profileActions = msg.actions
+			m.profileIdx = 0
+		}
+		m.err = msg.err
+
+	case reactionsMsg:
+		m.reactions = msg.reactions
+		m.err = msg.err
+
+	case reactionToggledMsg:
+		if msg.err == nil && msg.post != nil {
+			for i := range m.posts {
+				if m.posts[i].ID == msg.post.ID {
+					m.posts[i] = *msg.post
+				}
+			}
+			m.refreshTopicDetail()
+		}
+		m.err = msg.err
+
+	case pollVotedMsg:
+		if msg.err == nil && msg.poll != nil {
+			for i := range m.posts {
+				if m.posts[i].ID != msg.postID {
+					continue
+				}
+				for j := range m.posts[i].Polls {
+					if m.posts[i].Polls[j].Name == msg.pollName {
+						m.posts[i].Polls[j] = *msg.poll
+					}
+				}
+				if m.posts[i].PollsVotes == nil {
+					m.posts[i].PollsVotes = make(map[string][]string)
+				}
+				m.posts[i].PollsVotes[msg.pollName] = msg.votes
+			}
+			if post, poll, ok := m.currentPoll(); ok {
+				m.pollChoices = votedChoices(post, poll)
+			}
+			m.refreshTopicDetail()
+		}
+		m.err = msg.err
+
+	case fileUploadedMsg:
+		if msg.err == nil {
+			m.composer.InsertString(msg.upload.Markdown() + "\n")
+			m.notice = "📎 已插入: " + msg.upload.OriginalFilename
+		} else {
+			m.notice = ""
+		}
+		m.err = msg.err
+
+	case postFlaggedMsg:
+		if msg.err == nil {
+			m.notice = "🚩 已举报，感谢你的反馈"
+		}
+		m.err = msg.err
+
+	case userActionsMsg:
+		if msg.err == nil {
+			m.profileActions = append(m.profileActions, msg.actions...)
 		}
 		m.err = msg.err
 
@@ -256,6 +640,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
+	if m.state == composerView && m.attaching {
+		m.attachInput, cmd = m.attachInput.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if m.state == flagView && m.flagStep == flagStepMessage {
+		m.flagInput, cmd = m.flagInput.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
@@ -264,26 +658,25 @@ func (m Model) updateTopicList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, keys.Quit):
 		return m, tea.Quit
 	case key.Matches(msg, keys.Up):
-		if m.selected > 0 {
-			m.selected--
-		}
+		return m.moveTopicSelection(-1)
 	case key.Matches(msg, keys.Down):
-		if m.selected < len(m.topics)-1 {
-			m.selected++
-		}
-		if m.selected >= len(m.topics)-5 && m.moreTopicsURL != "" && !m.loading {
-			m.loading = true
-			return m, m.loadMoreTopics
-		}
+		return m.moveTopicSelection(1)
+	case key.Matches(msg, keys.Top):
+		m.selected = 0
+	case key.Matches(msg, keys.Last):
+		m.selected = max(len(m.topics)-1, 0)
 	case key.Matches(msg, keys.Enter):
 		if len(m.topics) > 0 {
 			m.state = topicDetailView
 			return m, m.fetchTopicDetail(m.topics[m.selected].ID)
 		}
+	case key.Matches(msg, keys.NewTab):
+		if len(m.topics) > 0 {
+			return m.openInNewTab(m.topics[m.selected].ID)
+		}
 	case key.Matches(msg, keys.Open):
 		if len(m.topics) > 0 {
-			topicID := m.topics[m.selected].ID
-			openInBrowser(fmt.Sprintf("https://linux.do/t/%d", topicID))
+			return m, openInBrowser(m.client.TopicURL(m.topics[m.selected].ID, 0))
 		}
 	case key.Matches(msg, keys.LoadMore):
 		if m.moreTopicsURL != "" && !m.loading {
@@ -291,22 +684,24 @@ func (m Model) updateTopicList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, m.loadMoreTopics
 		}
 	case key.Matches(msg, keys.Filter):
-		filters := []string{"latest", "hot", "new", "top"}
-		for i, f := range filters {
+		for i, f := range topicFilters {
 			if f == m.filter {
-				m.filter = filters[(i+1)%len(filters)]
+				m.filter = topicFilters[(i+1)%len(topicFilters)]
 				break
 			}
 		}
-		m.selected = 0
-		m.topics = nil
-		m.moreTopicsURL = ""
-		return m, m.fetchTopics
+		m.category = nil
+		return m.reloadTopics()
 	case key.Matches(msg, keys.Refresh):
-		m.selected = 0
-		m.topics = nil
-		m.moreTopicsURL = ""
-		return m, m.fetchTopics
+		return m.reloadTopics()
+	case key.Matches(msg, keys.Layout):
+		return m.toggleLayout()
+	case key.Matches(msg, keys.Period):
+		return m.cyclePeriod()
+	case key.Matches(msg, keys.Sort):
+		return m.cycleOrder()
+	case key.Matches(msg, keys.Reverse):
+		return m.toggleAscending()
 	case key.Matches(msg, keys.Search):
 		// 进入搜索输入模式
 		m.state = searchInputView
@@ -317,27 +712,54 @@ func (m Model) updateTopicList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// moveTopicSelection 在话题列表中移动选中项，接近末尾时自动加载更多
+func (m Model) moveTopicSelection(delta int) (tea.Model, tea.Cmd) {
+	m.selected = max(min(m.selected+delta, len(m.topics)-1), 0)
+	if delta > 0 && m.selected >= len(m.topics)-5 && m.moreTopicsURL != "" && !m.loading {
+		m.loading = true
+		return m, m.loadMoreTopics
+	}
+	return m, nil
+}
+
 func (m Model) updateTopicDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
+	// 话题内搜索激活时 n/N 优先用于切换匹配
+	case m.findQuery != "" && key.Matches(msg, keys.FindNext):
+		return m.findNext(1)
+	case m.findQuery != "" && key.Matches(msg, keys.FindPrev):
+		return m.findNext(-1)
 	case key.Matches(msg, keys.Quit):
 		return m, tea.Quit
 	case key.Matches(msg, keys.Back):
-		// 如果有搜索结果，返回搜索结果页面；否则返回话题列表
-		if len(m.searchResults) > 0 {
-			m.state = searchResultView
-		} else {
-			m.state = topicListView
+		// 先清除话题内搜索
+		if m.findQuery != "" {
+			m.clearFind()
+			return m, nil
 		}
+		// 如果有搜索结果，返回搜索结果页面；否则返回话题列表
+		m.state = m.listState()
+		m.checkpoint()
+	case key.Matches(msg, keys.CloseTab):
+		return m.closeTab(), nil
 	case key.Matches(msg, keys.Open):
 		if m.topicDetail != nil {
-			openInBrowser(fmt.Sprintf("https://linux.do/t/%d", m.topicDetail.ID))
+			return m, openInBrowser(m.client.TopicURL(m.topicDetail.ID, 0))
 		}
 	case key.Matches(msg, keys.Reply):
+		if m.topicDetail == nil {
+			return m, nil
+		}
 		m.state = composerView
-		m.replyToPost = 0
+		m.notice = ""
+		m.err = nil
+		m.replyToPost = m.replyToCurrent()
 		m.composer.Reset()
 		m.composer.Focus()
-		return m, textarea.Blink
+		m.draftSaved = ""
+		m.draftSequence = 0
+		m.draftGen++
+		return m, tea.Batch(textarea.Blink, m.loadDraft(m.topicDetail.ID), draftTick(m.draftGen))
 	case key.Matches(msg, keys.Like):
 		if len(m.posts) > m.currentPostIdx {
 			post := m.posts[m.currentPostIdx]
@@ -346,6 +768,23 @@ func (m Model) updateTopicDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, m.likePost(post.ID)
 		}
+	case key.Matches(msg, keys.PrevPost):
+		return m.moveCursor(-1)
+	case key.Matches(msg, keys.NextPost):
+		return m.moveCursor(1)
+	case key.Matches(msg, keys.Bookmark):
+		// 收藏/取消收藏光标所在的帖子
+		if len(m.posts) > m.currentPostIdx {
+			return m, m.toggleBookmark(m.posts[m.currentPostIdx])
+		}
+	case key.Matches(msg, keys.Copy), key.Matches(msg, keys.CopyLink):
+		// 复制光标所在帖子的内容或链接
+		if m.topicDetail != nil && len(m.posts) > m.currentPostIdx {
+			return m, m.copyPost(m.posts[m.currentPostIdx], key.Matches(msg, keys.CopyLink))
+		}
+	case key.Matches(msg, keys.Find):
+		// 在已加载的帖子中搜索
+		return m.startFind()
 	case key.Matches(msg, keys.LoadMore):
 		// 加载更多回复
 		return m, m.loadMorePosts()
@@ -355,19 +794,120 @@ func (m Model) updateTopicDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.jumpInput.Reset()
 		m.jumpInput.Focus()
 		return m, textarea.Blink
+	case key.Matches(msg, keys.Top):
+		// 回到第一个已加载的帖子
+		m.viewport.GotoTop()
+		return m.moveCursor(-m.currentPostIdx)
 	case key.Matches(msg, keys.Last):
 		// 跳转到最后一条
 		return m, m.jumpToLast()
+	case key.Matches(msg, keys.Watch):
+		// 循环切换通知级别
+		if m.topicDetail != nil {
+			level := nextNotificationLevel(m.topicDetail.NotificationLevel())
+			return m, m.setNotificationLevel(m.topicDetail.ID, level)
+		}
+	case key.Matches(msg, keys.React):
+		// 打开表情回应选择器
+		if len(m.posts) > m.currentPostIdx {
+			m.state = reactionPickerView
+			m.reactionIdx = 0
+			if len(m.reactions) == 0 {
+				return m, m.fetchReactions
+			}
+		}
+	case key.Matches(msg, keys.Poll):
+		// 参与当前帖子中的投票
+		if len(m.posts) > m.currentPostIdx && len(m.posts[m.currentPostIdx].Polls) > 0 {
+			post := m.posts[m.currentPostIdx]
+			m.state = pollView
+			m.pollIdx = 0
+			m.pollCursor = 0
+			m.pollChoices = votedChoices(post, post.Polls[0])
+			m.err = nil
+		}
+	case key.Matches(msg, keys.Editor):
+		// 在外部编辑器中回复，预填草稿
+		if m.topicDetail != nil {
+			m.replyToPost = m.replyToCurrent()
+			m.err = nil
+			return m, m.editWithDraft(m.topicDetail.ID)
+		}
+	case key.Matches(msg, keys.Quote):
+		// 引用当前帖子并在外部编辑器中回复
+		if m.topicDetail != nil && len(m.posts) > m.currentPostIdx {
+			post := m.posts[m.currentPostIdx]
+			m.replyToPost = post.PostNumber
+			m.err = nil
+			return m, openEditor(m.quoteText(post))
+		}
+	case key.Matches(msg, keys.Images):
+		return m.toggleImages()
+	case key.Matches(msg, keys.View):
+		// 全屏查看当前帖子的图片
+		if len(m.posts) > m.currentPostIdx {
+			if cmd := m.viewPostImages(m.posts[m.currentPostIdx]); cmd != nil {
+				return m, cmd
+			}
+			m.notice = "当前帖子没有图片"
+		}
+	case key.Matches(msg, keys.Links):
+		// 列出当前帖子中的链接
+		return m.openLinkPicker()
+	case key.Matches(msg, keys.Flag):
+		// 举报当前帖子
+		if len(m.posts) > m.currentPostIdx {
+			m.state = flagView
+			m.flagIdx = 0
+			m.flagStep = flagStepChoose
+		}
+	case key.Matches(msg, keys.Profile):
+		// 查看当前帖子作者的资料
+		if len(m.posts) > m.currentPostIdx {
+			m.state = userProfileView
+			m.profile = nil
+			m.profileSummary = nil
+			m.profileActions = nil
+			m.err = nil
+			return m, m.fetchUserProfile(m.posts[m.currentPostIdx].Username)
+		}
+	default:
+		// 其余按键交给 viewport，按 scroll_up/page_down 等快捷键逐行或翻页滚动
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
 	}
 	return m, nil
 }
 
 func (m Model) updateComposer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.attaching {
+		return m.updateAttachInput(msg)
+	}
+
 	switch msg.Type {
 	case tea.KeyEsc:
+		// 取消前保存草稿，下次打开时自动恢复
+		cmd := m.saveDraftIfChanged()
 		m.state = topicDetailView
 		m.composer.Reset()
+		m.draftSaved = ""
+		return m, cmd
+	case tea.KeyCtrlR:
+		// 切换 Markdown 预览
+		m.showPreview = !m.showPreview
+		m.resizeComposer()
 		return m, nil
+	case tea.KeyCtrlX:
+		// 在外部编辑器中继续编辑当前内容
+		return m, openEditor(m.composer.Value())
+	case tea.KeyCtrlO:
+		// 输入本地文件路径上传附件
+		m.attaching = true
+		m.composer.Blur()
+		m.attachInput.Reset()
+		m.attachInput.Focus()
+		return m, textarea.Blink
 	case tea.KeyCtrlD:
 		content := strings.TrimSpace(m.composer.Value())
 		if len(content) > 0 {
@@ -414,14 +954,13 @@ func (m Model) updateSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.state = topicListView
 		m.searchInput.Reset()
 		return m, nil
+	case tea.KeyUp, tea.KeyDown:
+		m.browseSearchHistory(msg.Type == tea.KeyUp)
+		return m, nil
 	case tea.KeyEnter:
 		query := strings.TrimSpace(m.searchInput.Value())
 		if query != "" {
-			m.searchQuery = query
-			m.searchPage = 1
-			m.state = searchResultView
-			m.selected = 0
-			return m, m.performSearch(query, 1)
+			return m.search(query)
 		}
 		m.state = topicListView
 		m.searchInput.Reset()
@@ -433,6 +972,38 @@ func (m Model) updateSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// search 全站搜索并显示第一页结果
+func (m Model) search(query string) (tea.Model, tea.Cmd) {
+	m.saved.AddSearch(query)
+	m.searchQuery = query
+	m.searchPage = 1
+	m.state = searchResultView
+	m.selected = 0
+	return m, m.performSearch(query, 1)
+}
+
+// reloadTopics 按当前的列表类型和分类重新加载话题列表
+func (m Model) reloadTopics() (tea.Model, tea.Cmd) {
+	m.state = topicListView
+	m.selected = 0
+	m.topics = nil
+	m.moreTopicsURL = ""
+	m.previews.reset()
+	return m, m.fetchTopics
+}
+
+// toggleImages 切换帖子内图片预览
+func (m Model) toggleImages() (tea.Model, tea.Cmd) {
+	m.showImages = !m.showImages
+	if m.showImages {
+		m.notice = "🖼  已开启图片预览"
+	} else {
+		m.notice = "已关闭图片预览"
+	}
+	m.refreshTopicDetail()
+	return m, m.loadPostImages()
+}
+
 func (m Model) updateSearchResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, keys.Quit):
@@ -454,10 +1025,13 @@ func (m Model) updateSearchResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			topicID := m.searchResults[m.selected].TopicID
 			return m, m.fetchTopicDetail(topicID)
 		}
+	case key.Matches(msg, keys.NewTab):
+		if len(m.searchResults) > 0 {
+			return m.openInNewTab(m.searchResults[m.selected].TopicID)
+		}
 	case key.Matches(msg, keys.Open):
 		if len(m.searchResults) > 0 {
-			topicID := m.searchResults[m.selected].TopicID
-			openInBrowser(fmt.Sprintf("https://linux.do/t/%d", topicID))
+			return m, openInBrowser(m.client.TopicURL(m.searchResults[m.selected].TopicID, 0))
 		}
 	case key.Matches(msg, keys.LoadMore):
 		// 加载下一页搜索结果
@@ -491,11 +1065,30 @@ func (m Model) View() string {
 		return m.renderSearchInput()
 	case searchResultView:
 		return m.renderSearchResult()
+	case userProfileView:
+		return m.renderUserProfile()
+	case reactionPickerView, findInputView:
+		return m.renderTopicView()
+	case pollView:
+		return m.renderPollView()
+	case flagView:
+		return m.renderFlag()
+	case editorConfirmView:
+		return m.renderEditorConfirm()
+	case linkPickerView:
+		return m.renderLinkPicker()
+	case helpView:
+		return m.renderHelp()
+	case paletteView:
+		return m.renderPalette()
 	}
 
 	return ""
 }
 
+// topicFilters 话题列表的过滤方式，按切换顺序排列
+var topicFilters = []string{"latest", "hot", "new", "top"}
+
 func getFilterEmoji(filter string) string {
 	switch filter {
 	case "latest":
@@ -511,24 +1104,57 @@ func getFilterEmoji(filter string) string {
 	}
 }
 
-func (m Model) renderTopicList() string {
+// offlineIndicator 离线模式或论坛无法访问、显示的是缓存内容时在标题后显示标记
+func (m Model) offlineIndicator() string {
+	if !m.client.Offline() {
+		return ""
+	}
+	return " " + loadingStyle.Render("📴 离线")
+}
+
+// renderWarnings 显示启动时的配置警告，每条一行，后面空一行
+func (m Model) renderWarnings() string {
+	if len(m.warnings) == 0 {
+		return ""
+	}
 	var s strings.Builder
+	for _, w := range m.warnings {
+		s.WriteString(loadingStyle.Render("⚠️ "+w) + "\n")
+	}
+	s.WriteString(helpStyle.Render("按任意键关闭提示") + "\n\n")
+	return s.String()
+}
 
-	emoji := getFilterEmoji(m.filter)
-	title := fmt.Sprintf(" %s Linux.do - %s ", emoji, m.filter)
-	s.WriteString(titleStyle.Render(title) + "\n\n")
+// listTitle 话题列表的标题，显示分类时标题中带分类名
+func (m Model) listTitle() string {
+	if m.category != nil {
+		return fmt.Sprintf(" 📂 Linux.do - %s ", m.category.Name)
+	}
+	return fmt.Sprintf(" %s Linux.do ", getFilterEmoji(m.filter))
+}
 
-	if m.err != nil {
-		s.WriteString(fmt.Sprintf("❌ 错误: %v\n\n", m.err))
+// renderListTitle 渲染话题列表标题和后面可以点击切换的过滤标签
+func (m Model) renderListTitle() string {
+	parts := []string{titleStyle.Render(m.listTitle())}
+	for _, f := range topicFilters {
+		if m.category == nil && f == m.filter {
+			parts = append(parts, selectedStyle.Render(" "+f+" "))
+		} else {
+			parts = append(parts, helpStyle.Render(" "+f+" "))
+		}
 	}
+	return strings.Join(parts, " ")
+}
 
-	maxVisible := m.height - 8
+// topicListWindow 返回话题列表中显示的范围 [start, end) 和最多显示的行数，使选中的话题居中
+func (m Model) topicListWindow() (start, end, maxVisible int) {
+	maxVisible = m.height - 8
 	if maxVisible < 10 {
 		maxVisible = 10
 	}
 
-	start := 0
-	end := len(m.topics)
+	start = 0
+	end = len(m.topics)
 
 	if len(m.topics) > maxVisible {
 		halfVisible := maxVisible / 2
@@ -545,45 +1171,82 @@ func (m Model) renderTopicList() string {
 			}
 		}
 	}
+	return start, end, maxVisible
+}
 
-	for i := start; i < end && i < len(m.topics); i++ {
-		topic := m.topics[i]
+func (m Model) renderTopicList() string {
+	var s strings.Builder
 
-		titleWidth := 50
-		if m.width > 100 {
-			titleWidth = m.width - 50
-		}
+	s.WriteString(m.renderListTitle() + m.offlineIndicator() + "\n")
+	s.WriteString(m.renderTabBar() + "\n")
 
-		truncatedTitle := truncate(topic.Title, titleWidth)
-		paddedTitle := padRight(truncatedTitle, titleWidth)
+	s.WriteString(m.renderWarnings())
+	if m.err != nil {
+		s.WriteString(fmt.Sprintf("❌ 错误: %v\n\n", m.err))
+	}
 
-		line := fmt.Sprintf("%3d. %s  💬 回复 %4d  👀 浏览 %6d",
-			i+1,
-			paddedTitle,
-			topic.ReplyCount,
-			topic.Views,
-		)
+	start, end, maxVisible := m.topicListWindow()
 
-		if i == m.selected {
-			s.WriteString(selectedStyle.Render(line) + "\n")
-		} else {
-			s.WriteString(line + "\n")
+	if m.splitView() {
+		s.WriteString(m.renderSplitList(start, end, maxVisible))
+	} else {
+		for i := start; i < end && i < len(m.topics); i++ {
+			topic := m.topics[i]
+
+			titleWidth := 50
+			if m.width > 100 {
+				titleWidth = m.width - 50
+			}
+
+			truncatedTitle := truncate(topic.Title, titleWidth)
+			paddedTitle := padRight(truncatedTitle, titleWidth)
+
+			line := fmt.Sprintf("%3d. %s  💬 回复 %4d  👀 浏览 %6d",
+				i+1,
+				paddedTitle,
+				topic.ReplyCount,
+				topic.Views,
+			)
+
+			if i == m.selected {
+				s.WriteString(selectedStyle.Render(line) + "\n")
+			} else {
+				s.WriteString(line + "\n")
+			}
 		}
 	}
 
 	s.WriteString("\n")
 
 	statusLine := fmt.Sprintf("已加载: %d 条", len(m.topics))
+	if order := m.orderStatus(); order != "" {
+		statusLine += "  " + order
+	}
 	if m.loading {
 		statusLine += " " + loadingStyle.Render("(加载中...)")
 	} else if m.moreTopicsURL != "" {
-		statusLine += " (按 n 加载更多)"
+		statusLine += fmt.Sprintf(" (按 %s 加载更多)", keys.LoadMore.Help().Key)
 	} else {
 		statusLine += " (已全部加载)"
 	}
 	s.WriteString(helpStyle.Render(statusLine) + "\n")
 
-	helpText := "↑/↓: 移动 | Enter: 打开 | o: 浏览器 | n: 更多 | f: 切换 | g: 刷新 | s: 搜索 | q: 退出"
+	helpText := helpLine(
+		helpPair(keys.Up, keys.Down, "移动"),
+		helpKey(keys.Enter),
+		helpKey(keys.NewTab),
+		m.tabHelp(),
+		helpKey(keys.Open),
+		helpKey(keys.LoadMore),
+		helpKey(keys.Filter),
+		helpKey(keys.Period),
+		helpKey(keys.Sort),
+		helpKey(keys.Refresh),
+		helpKey(keys.Search),
+		helpKey(keys.Layout),
+		helpKey(keys.Help),
+		helpKey(keys.Quit),
+	)
 	s.WriteString(helpStyle.Render(helpText))
 
 	return s.String()
@@ -591,11 +1254,16 @@ func (m Model) renderTopicList() string {
 
 func (m Model) renderTopicView() string {
 	if m.topicDetail == nil {
+		if bar := m.renderTabBar(); bar != "" {
+			return bar + "\n\n加载中..."
+		}
 		return "加载中..."
 	}
 
 	var s strings.Builder
-	s.WriteString(titleStyle.Render(fmt.Sprintf(" 💬 %s ", m.topicDetail.Title)) + "\n\n")
+	s.WriteString(titleStyle.Render(fmt.Sprintf(" 💬 %s ", m.topicDetail.Title)))
+	s.WriteString(" " + helpStyle.Render(notificationIndicator(m.topicDetail.NotificationLevel())) + m.offlineIndicator() + "\n")
+	s.WriteString(m.renderTabBar() + "\n")
 	s.WriteString(m.viewport.View())
 	s.WriteString("\n\n")
 
@@ -605,45 +1273,136 @@ func (m Model) renderTopicView() string {
 		currentFloor = m.posts[m.currentPostIdx].PostNumber
 	}
 	statusLine := fmt.Sprintf("已加载: %d/%d 楼  当前: %d 楼", len(m.posts), m.topicDetail.PostsCount, currentFloor)
+	if m.notice != "" {
+		statusLine += "  " + m.notice
+	}
 	s.WriteString(helpStyle.Render(statusLine) + "\n")
+	if m.err != nil {
+		s.WriteString(fmt.Sprintf("❌ 错误: %v\n", m.err))
+	}
+	s.WriteString(m.renderWarnings())
+
+	if m.state == reactionPickerView {
+		s.WriteString(m.renderReactionPicker())
+		return s.String()
+	}
+	if m.state == findInputView {
+		s.WriteString(m.findInput.View() + "\n")
+		s.WriteString(helpStyle.Render("Enter: 搜索 | Esc: 取消"))
+		return s.String()
+	}
 
-	helpText := "r: 回复 | l: 点赞 | o: 浏览器 | n: 更多 | /: 跳转 | G: 末尾 | ↑/↓: 滚动 | Esc: 返回 | q: 退出"
+	helpText := helpLine(
+		helpPair(keys.NextPost, keys.PrevPost, "下/上一帖"),
+		helpPair(keys.ScrollUp, keys.ScrollDown, "滚动"),
+		helpKey(keys.Reply),
+		helpKey(keys.Editor),
+		helpKey(keys.Quote),
+		helpKey(keys.Like),
+		helpKey(keys.React),
+		helpKey(keys.Bookmark),
+		helpPair(keys.Copy, keys.CopyLink, "复制内容/链接"),
+		helpKey(keys.Links),
+		helpKey(keys.Images),
+		helpKey(keys.View),
+		helpKey(keys.Poll),
+		helpKey(keys.Flag),
+		helpKey(keys.Profile),
+		helpKey(keys.Watch),
+		helpKey(keys.Open),
+		helpKey(keys.LoadMore),
+		helpKey(keys.Find),
+		helpKey(keys.Jump),
+		helpKey(keys.Last),
+		m.tabHelp(),
+		helpKey(keys.CloseTab),
+		helpKey(keys.Help),
+		helpKey(keys.Back),
+		helpKey(keys.Quit),
+	)
 	s.WriteString(helpStyle.Render(helpText))
 
 	return s.String()
 }
 
-func (m Model) renderTopicDetail() string {
+// renderTopicDetail 渲染所有已加载的帖子，同时返回每个帖子的起始行和正文中链接的位置
+func (m Model) renderTopicDetail() (string, []int, []postLink) {
 	var s strings.Builder
+	offsets := make([]int, 0, len(m.posts))
+	var links []postLink
+	opts := m.renderOptions(m.width - 8)
+	opts.Mark = m.findQuery
+	bodies := m.bodies.begin()
+
+	// 逐段累计行数，避免每个帖子都重新扫描整个内容
+	lines := 0
+	write := func(text string) {
+		s.WriteString(text)
+		lines += strings.Count(text, "\n")
+	}
 
 	for i, post := range m.posts {
+		offsets = append(offsets, lines)
+
 		header := fmt.Sprintf("👤 @%s  #%d", post.Username, post.PostNumber)
+		if post.Bookmarked {
+			header += "  🔖"
+		}
 		if i == m.currentPostIdx {
-			header = "▶ " + header
+			write(selectedStyle.Copy().Bold(true).Render("▶ "+header) + "\n\n")
+		} else {
+			write(authorStyle.Render("  "+header) + "\n\n")
 		}
-		s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4")).Render(header) + "\n\n")
 
-		content := htmlToText(post.Cooked)
-		s.WriteString(wrapText(content, m.width-8) + "\n")
+		body, spans := bodies.body(post, opts, m.images.version)
+		for _, span := range spans {
+			span.Line += lines
+			links = append(links, postLink{post: i, LinkSpan: span})
+		}
+		write(body + "\n")
 
-		if m.isLiked(post) {
-			s.WriteString("\n❤️  已点赞\n")
+		for _, poll := range post.Polls {
+			write("\n" + renderPoll(post, poll, -1, nil, m.width))
+		}
+
+		if reactions := renderReactions(post); reactions != "" {
+			write("\n" + reactions + "\n")
+		} else if m.isLiked(post) {
+			write("\n❤️  已点赞\n")
 		}
 
 		if i < len(m.posts)-1 {
-			s.WriteString("\n" + strings.Repeat("─", min(m.width-4, 100)) + "\n\n")
+			write("\n" + strings.Repeat("─", min(m.width-4, 100)) + "\n\n")
 		}
 	}
+	m.bodies.end(bodies)
 
-	return s.String()
+	return s.String(), offsets, links
 }
 
 func (m Model) renderComposer() string {
 	var s strings.Builder
-	s.WriteString(titleStyle.Render(" ✍️  回复主题 ") + "\n")
+	title := " ✍️  回复主题 "
+	if m.replyToPost > 0 {
+		title = fmt.Sprintf(" ✍️  回复 #%d楼 ", m.replyToPost)
+	}
+	s.WriteString(titleStyle.Render(title) + "\n")
 	s.WriteString(helpStyle.Render("输入你的回复内容 (支持 Markdown)") + "\n\n")
-	s.WriteString(m.composer.View() + "\n\n")
-	helpText := "Ctrl+D: 发送 | Esc: 取消"
+	s.WriteString(m.renderComposerBody() + "\n\n")
+
+	if m.err != nil {
+		s.WriteString(fmt.Sprintf("❌ 错误: %v\n", m.err))
+	}
+	if m.attaching {
+		s.WriteString("附件路径:\n" + m.attachInput.View() + "\n")
+		s.WriteString(helpStyle.Render("Enter: 上传 | Esc: 取消"))
+		return s.String()
+	}
+	if m.notice != "" {
+		s.WriteString(helpStyle.Render(m.notice) + "\n")
+	}
+
+	helpText := "Ctrl+D: 发送 | Ctrl+R: 预览 | Ctrl+O: 附件 | Ctrl+X: 外部编辑器 | Esc: 取消"
 	s.WriteString(helpStyle.Render(helpText))
 	return s.String()
 }
@@ -662,43 +1421,33 @@ func (m Model) renderSearchInput() string {
 	var s strings.Builder
 	s.WriteString(titleStyle.Render(" 🔍 搜索 ") + "\n\n")
 	s.WriteString(m.searchInput.View() + "\n\n")
-	helpText := "Enter: 搜索 | Esc: 取消"
+	if len(m.saved.Searches) > 0 {
+		recent := m.saved.Searches[:min(len(m.saved.Searches), 5)]
+		s.WriteString(helpStyle.Render("最近搜索: "+strings.Join(recent, " · ")) + "\n\n")
+	}
+	helpText := "Enter: 搜索 | ↑/↓: 搜索历史 | Esc: 取消"
 	s.WriteString(helpStyle.Render(helpText))
 	return s.String()
 }
 
-func (m Model) renderSearchResult() string {
-	var s strings.Builder
-
-	title := fmt.Sprintf(" 🔍 搜索结果: %s (第 %d 页) ", m.searchQuery, m.searchPage)
-	s.WriteString(titleStyle.Render(title) + "\n\n")
+// searchLinesPerResult 每个搜索结果占3行（标题、信息、内容）+ 1行空白 = 4行
+const searchLinesPerResult = 4
 
-	if m.err != nil {
-		s.WriteString(fmt.Sprintf("❌ 错误: %v\n\n", m.err))
-	}
-
-	if len(m.searchResults) == 0 {
-		s.WriteString("没有找到相关结果\n\n")
-		helpText := "s: 重新搜索 | Esc: 返回 | q: 退出"
-		s.WriteString(helpStyle.Render(helpText))
-		return s.String()
-	}
-
-	// 每个结果占3行（标题、信息、内容）+ 1行空白 = 4行
-	linesPerResult := 4
+// searchResultWindow 返回搜索结果中显示的范围 [start, end)，确保选中项可见
+func (m Model) searchResultWindow() (start, end int) {
 	availableLines := m.height - 8 // 减去标题、状态栏等
 	if availableLines < 20 {
 		availableLines = 20
 	}
 
-	maxVisible := availableLines / linesPerResult
+	maxVisible := availableLines / searchLinesPerResult
 	if maxVisible < 3 {
 		maxVisible = 3
 	}
 
 	// 简单的滚动逻辑：确保选中项可见
-	start := 0
-	end := len(m.searchResults)
+	start = 0
+	end = len(m.searchResults)
 
 	if len(m.searchResults) > maxVisible {
 		// 如果选中项在可见范围之外，调整起始位置
@@ -715,16 +1464,28 @@ func (m Model) renderSearchResult() string {
 			}
 		}
 	}
+	return start, end
+}
+
+func (m Model) renderSearchResult() string {
+	var s strings.Builder
 
-	// 高亮样式
-	highlightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFF00")).
-		Bold(true)
+	title := fmt.Sprintf(" 🔍 搜索结果: %s (第 %d 页) ", m.searchQuery, m.searchPage)
+	s.WriteString(titleStyle.Render(title) + m.offlineIndicator() + "\n")
+	s.WriteString(m.renderTabBar() + "\n")
 
-	// 左侧光标样式 - 使用半方块字符
-	cursorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#7D56F4")).
-		Bold(true)
+	if m.err != nil {
+		s.WriteString(fmt.Sprintf("❌ 错误: %v\n\n", m.err))
+	}
+
+	if len(m.searchResults) == 0 {
+		s.WriteString("没有找到相关结果\n\n")
+		helpText := helpLine(keys.Search.Help().Key+": 重新搜索", helpKey(keys.Back), helpKey(keys.Quit))
+		s.WriteString(helpStyle.Render(helpText))
+		return s.String()
+	}
+
+	start, end := m.searchResultWindow()
 
 	for i := start; i < end && i < len(m.searchResults); i++ {
 		result := m.searchResults[i]
@@ -785,7 +1546,17 @@ func (m Model) renderSearchResult() string {
 		m.searchPage, len(m.searchResults), m.selected+1, len(m.searchResults), start+1, end)
 	s.WriteString(helpStyle.Render(statusLine) + "\n")
 
-	helpText := "↑/↓: 滚动 | Enter: 查看详情 | o: 浏览器 | n: 下一页 | s: 重新搜索 | Esc: 返回 | q: 退出"
+	helpText := helpLine(
+		helpPair(keys.Up, keys.Down, "移动"),
+		keys.Enter.Help().Key+": 查看详情",
+		helpKey(keys.NewTab),
+		m.tabHelp(),
+		helpKey(keys.Open),
+		keys.LoadMore.Help().Key+": 下一页",
+		keys.Search.Help().Key+": 重新搜索",
+		helpKey(keys.Back),
+		helpKey(keys.Quit),
+	)
 	s.WriteString(helpStyle.Render(helpText))
 
 	return s.String()
@@ -793,7 +1564,7 @@ func (m Model) renderSearchResult() string {
 
 func (m Model) isLiked(post client.Post) bool {
 	for _, action := range post.ActionsSummary {
-		if action.ID == 2 && action.Acted {
+		if action.ID == client.PostActionLike && action.Acted {
 			return true
 		}
 	}
@@ -809,6 +1580,7 @@ type topicListMsg struct {
 }
 
 type topicDetailMsg struct {
+	tabID      int // 发起请求时所在的标签页，0 表示还没有打开的标签页
 	detail     *client.TopicDetail
 	posts      []client.Post
 	allPostIDs []int
@@ -816,11 +1588,13 @@ type topicDetailMsg struct {
 }
 
 type morePostsMsg struct {
-	posts []client.Post
-	err   error
+	topicID int
+	posts   []client.Post
+	err     error
 }
 
 type jumpToPostMsg struct {
+	topicID   int
 	posts     []client.Post
 	targetIdx int
 	err       error
@@ -835,19 +1609,27 @@ type searchResultMsg struct {
 	err     error
 }
 
+type notificationLevelMsg struct {
+	topicID int
+	level   client.NotificationLevel
+	err     error
+}
+
 func (m Model) fetchTopics() tea.Msg {
 	var topics *client.TopicList
 	var err error
 
-	switch m.filter {
-	case "hot":
+	switch {
+	case m.category != nil:
+		topics, err = m.client.GetCategoryTopics(*m.category, m.order)
+	case m.filter == "hot":
 		topics, err = m.client.GetHotTopics()
-	case "new":
+	case m.filter == "new":
 		topics, err = m.client.GetNewTopics()
-	case "top":
-		topics, err = m.client.GetTopTopics("weekly")
+	case m.filter == "top":
+		topics, err = m.client.GetTopTopics(m.period)
 	default:
-		topics, err = m.client.GetLatestTopics()
+		topics, err = m.client.GetLatestTopicsOrdered(m.order)
 	}
 
 	if err != nil {
@@ -891,12 +1673,14 @@ func (m Model) loadMoreTopics() tea.Msg {
 }
 
 func (m Model) fetchTopicDetail(topicID int) tea.Cmd {
+	tabID := m.activeTabID()
 	return func() tea.Msg {
 		detail, err := m.client.GetTopic(topicID)
 		if err != nil {
-			return topicDetailMsg{err: err}
+			return topicDetailMsg{tabID: tabID, err: err}
 		}
 		return topicDetailMsg{
+			tabID:      tabID,
 			detail:     detail,
 			posts:      detail.PostStream.Posts,
 			allPostIDs: detail.PostStream.Stream,
@@ -904,96 +1688,98 @@ func (m Model) fetchTopicDetail(topicID int) tea.Cmd {
 	}
 }
 
+// loadMorePosts 加载最后一个已加载帖子之后的一批帖子，优先使用预取的帖子；
+// 这批帖子正在预取时等预取完成后再加载
 func (m Model) loadMorePosts() tea.Cmd {
-	return func() tea.Msg {
-		if m.topicDetail == nil || len(m.allPostIDs) == 0 {
-			return morePostsMsg{}
-		}
-
-		// 获取下一批帖子ID（每次加载20个）
-		currentLen := len(m.posts)
-		if currentLen >= len(m.allPostIDs) {
-			return morePostsMsg{} // 已全部加载
-		}
+	if m.topicDetail == nil || len(m.allPostIDs) == 0 {
+		return func() tea.Msg { return morePostsMsg{} }
+	}
 
-		batchSize := 20
-		end := currentLen + batchSize
-		if end > len(m.allPostIDs) {
-			end = len(m.allPostIDs)
-		}
+	start := m.nextUnloadedIdx()
+	if start >= len(m.allPostIDs) {
+		return func() tea.Msg { return morePostsMsg{} } // 已全部加载
+	}
 
-		postIDs := m.allPostIDs[currentLen:end]
-		posts, err := m.client.GetPostsByIDs(m.topicDetail.ID, postIDs)
+	topicID := m.topicDetail.ID
+	ids := m.allPostIDs[start:min(start+postBatchSize, len(m.allPostIDs))]
+	have, missing := m.collectPosts(ids)
+	if len(missing) > 0 && m.prefetch.pending[missing[0]] {
+		m.prefetch.awaiting = topicID
+		return nil
+	}
 
+	return func() tea.Msg {
+		posts, err := m.client.GetPostsByIDs(topicID, missing)
 		return morePostsMsg{
-			posts: posts,
-			err:   err,
+			topicID: topicID,
+			posts:   orderPosts(ids, have, posts),
+			err:     err,
 		}
 	}
 }
 
+// jumpToFloor 跳转到指定楼层。目标楼层已加载时直接移动光标；离已加载的帖子不远时并发补齐中间的帖子，
+// 否则只加载目标楼层前后各 10 条
 func (m Model) jumpToFloor(floor int) tea.Cmd {
-	return func() tea.Msg {
-		if m.topicDetail == nil || floor < 1 || floor > m.topicDetail.PostsCount {
-			return jumpToPostMsg{err: fmt.Errorf("无效的楼层号")}
-		}
+	if m.topicDetail == nil || floor < 1 || floor > m.topicDetail.PostsCount {
+		return func() tea.Msg { return jumpToPostMsg{err: fmt.Errorf("无效的楼层号")} }
+	}
+	topicID := m.topicDetail.ID
 
-		// 检查是否已加载
-		for i, post := range m.posts {
-			if post.PostNumber == floor {
-				return jumpToPostMsg{
-					posts:     m.posts,
-					targetIdx: i,
-				}
+	// 检查是否已加载
+	for i, post := range m.posts {
+		if post.PostNumber == floor {
+			posts := m.posts
+			return func() tea.Msg {
+				return jumpToPostMsg{topicID: topicID, posts: posts, targetIdx: i}
 			}
 		}
+	}
 
-		// 未加载，需要获取
-		// 找到对应的 postID
-		if floor-1 >= len(m.allPostIDs) {
-			return jumpToPostMsg{err: fmt.Errorf("楼层号超出范围")}
-		}
+	// 未加载，需要获取
+	idx := floor - 1
+	if idx >= len(m.allPostIDs) {
+		return func() tea.Msg { return jumpToPostMsg{err: fmt.Errorf("楼层号超出范围")} }
+	}
 
-		// 加载包含目标楼层的上下文（前后各10条）
-		start := floor - 11
-		if start < 0 {
-			start = 0
-		}
-		end := floor + 10
-		if end > len(m.allPostIDs) {
-			end = len(m.allPostIDs)
-		}
+	var keep []client.Post
+	start := max(idx-10, 0)
+	end := min(idx+11, len(m.allPostIDs))
+	if next := m.nextUnloadedIdx(); len(m.posts) > 0 && idx >= next && idx-next < jumpFillLimit {
+		keep = m.posts[:len(m.posts):len(m.posts)]
+		start = next
+	}
+	ids := m.allPostIDs[start:end]
+	have, missing := m.collectPosts(ids)
 
-		postIDs := m.allPostIDs[start:end]
-		posts, err := m.client.GetPostsByIDs(m.topicDetail.ID, postIDs)
+	return func() tea.Msg {
+		fetched, err := fetchPostsParallel(m.client, topicID, missing)
 		if err != nil {
 			return jumpToPostMsg{err: err}
 		}
+		posts := append(keep, orderPosts(ids, have, fetched)...)
 
 		// 找到目标索引
-		targetIdx := 0
-		for i, post := range posts {
-			if post.PostNumber == floor {
+		targetIdx := len(keep)
+		for i := len(keep); i < len(posts); i++ {
+			if posts[i].PostNumber == floor {
 				targetIdx = i
 				break
 			}
 		}
-
 		return jumpToPostMsg{
+			topicID:   topicID,
 			posts:     posts,
-			targetIdx: targetIdx,
+			targetIdx: min(targetIdx, len(posts)-1),
 		}
 	}
 }
 
 func (m Model) jumpToLast() tea.Cmd {
-	return func() tea.Msg {
-		if m.topicDetail == nil {
-			return jumpToPostMsg{}
-		}
-
-		return m.jumpToFloor(m.topicDetail.PostsCount)()
+	if m.topicDetail == nil {
+		return func() tea.Msg { return jumpToPostMsg{} }
 	}
+	return m.jumpToFloor(m.topicDetail.PostsCount)
 }
 
 func (m Model) performSearch(query string, page int) tea.Cmd {
@@ -1030,21 +1816,49 @@ func (m Model) unlikePost(postID int) tea.Cmd {
 	}
 }
 
-func openInBrowser(url string) error {
-	var cmd *exec.Cmd
+func (m Model) setNotificationLevel(topicID int, level client.NotificationLevel) tea.Cmd {
+	return func() tea.Msg {
+		err := m.client.SetTopicNotificationLevel(topicID, level)
+		return notificationLevelMsg{topicID: topicID, level: level, err: err}
+	}
+}
 
-	switch runtime.GOOS {
-	case "darwin":
-		cmd = exec.Command("open", url)
-	case "linux":
-		cmd = exec.Command("xdg-open", url)
-	case "windows":
-		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+// nextNotificationLevel 按 普通 → 跟踪 → 关注 → 静音 → 普通 的顺序循环
+func nextNotificationLevel(level client.NotificationLevel) client.NotificationLevel {
+	switch level {
+	case client.NotificationRegular:
+		return client.NotificationTracking
+	case client.NotificationTracking:
+		return client.NotificationWatching
+	case client.NotificationWatching:
+		return client.NotificationMuted
 	default:
-		return fmt.Errorf("unsupported platform")
+		return client.NotificationRegular
 	}
+}
 
-	return cmd.Start()
+func notificationIndicator(level client.NotificationLevel) string {
+	switch level {
+	case client.NotificationMuted:
+		return "🔇 " + level.String()
+	case client.NotificationTracking:
+		return "🔔 " + level.String()
+	case client.NotificationWatching:
+		return "👁 " + level.String()
+	default:
+		return "🔕 " + level.String()
+	}
+}
+
+// filterMutedTopics 过滤掉已静音的话题
+func filterMutedTopics(topics []client.Topic) []client.Topic {
+	filtered := topics[:0]
+	for _, topic := range topics {
+		if !topic.Muted() {
+			filtered = append(filtered, topic)
+		}
+	}
+	return filtered
 }
 
 func truncate(s string, maxLen int) string {
@@ -1105,53 +1919,6 @@ func wrapText(text string, width int) string {
 	return result.String()
 }
 
-func htmlToText(html string) string {
-	html = strings.ReplaceAll(html, "</p>", "\n\n")
-	html = strings.ReplaceAll(html, "<br>", "\n")
-	html = strings.ReplaceAll(html, "<br/>", "\n")
-	html = strings.ReplaceAll(html, "<br />", "\n")
-	html = strings.ReplaceAll(html, "</div>", "\n")
-	html = strings.ReplaceAll(html, "</li>", "\n")
-
-	html = strings.ReplaceAll(html, "<pre>", "\n```\n")
-	html = strings.ReplaceAll(html, "</pre>", "\n```\n")
-	html = strings.ReplaceAll(html, "<code>", "`")
-	html = strings.ReplaceAll(html, "</code>", "`")
-
-	html = strings.ReplaceAll(html, "<li>", "• ")
-
-	html = strings.ReplaceAll(html, "<blockquote>", "\n> ")
-	html = strings.ReplaceAll(html, "</blockquote>", "\n")
-
-	re := regexp.MustCompile(`<[^>]*>`)
-	text := re.ReplaceAllString(html, "")
-
-	text = strings.ReplaceAll(text, "&nbsp;", " ")
-	text = strings.ReplaceAll(text, "&lt;", "<")
-	text = strings.ReplaceAll(text, "&gt;", ">")
-	text = strings.ReplaceAll(text, "&amp;", "&")
-	text = strings.ReplaceAll(text, "&quot;", "\"")
-	text = strings.ReplaceAll(text, "&#39;", "'")
-
-	lines := strings.Split(text, "\n")
-	var cleaned []string
-	prevEmpty := false
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" {
-			if !prevEmpty {
-				cleaned = append(cleaned, "")
-			}
-			prevEmpty = true
-		} else {
-			cleaned = append(cleaned, line)
-			prevEmpty = false
-		}
-	}
-
-	return strings.TrimSpace(strings.Join(cleaned, "\n"))
-}
-
 func min(a, b int) int {
 	if a < b {
 		return a
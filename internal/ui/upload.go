@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lhpqaq/ldo/internal/client"
+)
+
+type fileUploadedMsg struct {
+	upload *client.Upload
+	err    error
+}
+
+// updateAttachInput 处理回复编辑器中的附件路径输入
+func (m Model) updateAttachInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.attaching = false
+		m.attachInput.Reset()
+		m.composer.Focus()
+		return m, textarea.Blink
+	case tea.KeyEnter:
+		path := strings.TrimSpace(m.attachInput.Value())
+		m.attaching = false
+		m.attachInput.Reset()
+		m.composer.Focus()
+		if path == "" {
+			return m, textarea.Blink
+		}
+		m.notice = "⏳ 上传中: " + path
+		return m, tea.Batch(textarea.Blink, m.uploadFile(path))
+	default:
+		var cmd tea.Cmd
+		m.attachInput, cmd = m.attachInput.Update(msg)
+		return m, cmd
+	}
+}
+
+func (m Model) uploadFile(path string) tea.Cmd {
+	return func() tea.Msg {
+		upload, err := m.client.UploadFile(path)
+		if err != nil {
+			return fileUploadedMsg{err: fmt.Errorf("上传 %s 失败: %w", path, err)}
+		}
+		return fileUploadedMsg{upload: upload}
+	}
+}